@@ -0,0 +1,82 @@
+// box.go
+// for rolling what makes each caught pokemon its own (ivs, nature) and showing the box
+package main
+
+import (
+	"fmt"     // for printing box entries
+	"strconv" // for parsing box ids
+	"strings" // for trimming "#"
+	"time"    // for caught-at times
+
+	"github.com/PietPadda/pokedexcli/internal/pokeapi" // for caught pokemon
+)
+
+// the 25 natures, PokeAPI's names
+var natures = []string{
+	"hardy", "lonely", "brave", "adamant", "naughty",
+	"bold", "docile", "relaxed", "impish", "lax",
+	"timid", "hasty", "serious", "jolly", "naive",
+	"modest", "mild", "quiet", "bashful", "rash",
+	"calm", "gentle", "sassy", "careful", "quirky",
+}
+
+// maxIV is the highest individual value a stat can roll
+const maxIV = 31
+
+// newCaughtPokemon rolls ivs and a nature for a pokemon just caught
+// the Pokedex hands out its id when it goes in the box
+func newCaughtPokemon(cfg *config, species string, level int, location string) pokeapi.CaughtPokemon {
+	return pokeapi.CaughtPokemon{
+		Species: species,
+		Level:   level,
+		IVs: pokeapi.IVs{
+			HP:             cfg.intn(maxIV + 1),
+			Attack:         cfg.intn(maxIV + 1),
+			Defense:        cfg.intn(maxIV + 1),
+			SpecialAttack:  cfg.intn(maxIV + 1),
+			SpecialDefense: cfg.intn(maxIV + 1),
+			Speed:          cfg.intn(maxIV + 1),
+		},
+		Nature:   natures[cfg.intn(len(natures))],
+		CaughtAt: time.Now(),
+		Location: location,
+	}
+}
+
+// parseBoxID parses a box id arg, "3" or "#3"
+func parseBoxID(command, arg string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("error: %s takes a box id like 3 or #3 (see box), got %s", command, arg)
+	}
+	return id, nil
+}
+
+// boxLine is one pokemon's line in the box list, eg "#3 sparky (pikachu) Lv. 12"
+func boxLine(caught pokeapi.CaughtPokemon) string {
+	name := caught.Species
+	if caught.Nickname != "" {
+		name = fmt.Sprintf("%s (%s)", caught.Nickname, caught.Species)
+	}
+	return fmt.Sprintf("#%d %s Lv. %d", caught.ID, name, caught.Level)
+}
+
+// printBoxDetails shows everything about one caught pokemon
+func printBoxDetails(caught pokeapi.CaughtPokemon) {
+	fmt.Println(boxLine(caught))
+	fmt.Printf("Nature: %s\n", caught.Nature)
+	fmt.Printf("IVs: HP %d, Atk %d, Def %d, SpA %d, SpD %d, Spe %d (total %d)\n",
+		caught.IVs.HP, caught.IVs.Attack, caught.IVs.Defense,
+		caught.IVs.SpecialAttack, caught.IVs.SpecialDefense, caught.IVs.Speed, caught.IVs.Total())
+
+	// caught-at check (pokemon from old saves don't know)
+	where := caught.Location
+	if where == "" {
+		where = "somewhere"
+	}
+	if caught.CaughtAt.IsZero() {
+		fmt.Printf("Caught at %s\n", where)
+		return
+	}
+	fmt.Printf("Caught at %s on %s\n", where, caught.CaughtAt.Format("2006-01-02 15:04"))
+}
@@ -0,0 +1,164 @@
+// catch.go
+// for working out whether a thrown ball catches a pokemon
+package main
+
+import (
+	"fmt"     // for errors
+	"math"    // for the shake check roots
+	"sort"    // for listing statuses
+	"strconv" // for parsing --hp
+	"strings" // for joining status names
+
+	"github.com/PietPadda/pokedexcli/internal/pokeapi" // for item names
+)
+
+// catch formulas, picked with the -catch-formula flag
+const (
+	catchFormulaCapture = "capture" // the real games' capture mechanics (default)
+	catchFormulaClassic = "classic" // the original guess from base experience
+)
+
+// status conditions and their capture bonus (gen III/IV values)
+var catchStatuses = map[string]float64{
+	"none":      1,
+	"sleep":     2,
+	"freeze":    2,
+	"paralysis": 1.5,
+	"poison":    1.5,
+	"burn":      1.5,
+}
+
+// pokeball is a ball you can throw, and how much it helps
+type pokeball struct {
+	item  string  // inventory item name (PokeAPI's)
+	label string  // for "Throwing a ... at"
+	bonus float64 // capture formula ball bonus (gen III/IV values)
+}
+
+// every ball catch --ball knows, keyed by its short name
+var catchBalls = map[string]pokeball{
+	"poke":   {item: pokeapi.ItemPokeBall, label: "Pokeball", bonus: 1},
+	"great":  {item: pokeapi.ItemGreatBall, label: "Great Ball", bonus: 1.5},
+	"ultra":  {item: pokeapi.ItemUltraBall, label: "Ultra Ball", bonus: 2},
+	"master": {item: pokeapi.ItemMasterBall, label: "Master Ball", bonus: 255}, // never fails
+}
+
+// ball short names, worst to best (for listing)
+var ballOrder = []string{"poke", "great", "ultra", "master"}
+
+// catchOptions are the --flags a catch command can take
+type catchOptions struct {
+	hpPercent int    // current hp as a percent of max, 100 = full (we never battle, so that's the default)
+	status    string // status condition, key of catchStatuses
+	ball      string // ball to throw, key of catchBalls
+}
+
+// parseCatchArgs splits catch args into the pokemon name and its options
+// eg "pikachu --hp 25 --status sleep", the name can be left out if there's a defaultName (a wild encounter)
+func parseCatchArgs(args []string, defaultName string) (string, catchOptions, error) {
+	opts := catchOptions{hpPercent: 100, status: "none", ball: "poke"} // wild pokemon, untouched, plain pokeball
+	name := ""
+
+	// loop thru args, flags take the next arg as their value
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// pokemon name check (first non flag arg)
+		if !strings.HasPrefix(arg, "--") {
+			if name != "" {
+				return "", catchOptions{}, fmt.Errorf("error: catch takes one pokemon name, got %s and %s", name, arg)
+			}
+			name = arg
+			continue
+		}
+
+		// value check, every flag takes one
+		if i+1 >= len(args) {
+			return "", catchOptions{}, fmt.Errorf("error: %s needs a value", arg)
+		}
+		value := args[i+1]
+		i++ // skip the value
+
+		switch arg {
+		case "--hp":
+			percent, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+			if err != nil || percent < 1 || percent > 100 {
+				return "", catchOptions{}, fmt.Errorf("error: --hp must be a percent from 1 to 100, got %s", value)
+			}
+			opts.hpPercent = percent
+		case "--status":
+			if _, ok := catchStatuses[value]; !ok {
+				return "", catchOptions{}, fmt.Errorf("error: unknown status %s (use %s)", value, statusNames())
+			}
+			opts.status = value
+		case "--ball":
+			short := strings.TrimSuffix(value, "-ball") // "great" or "great-ball"
+			if _, ok := catchBalls[short]; !ok {
+				return "", catchOptions{}, fmt.Errorf("error: unknown ball %s (use %s)", value, strings.Join(ballOrder, ", "))
+			}
+			opts.ball = short
+		default:
+			return "", catchOptions{}, fmt.Errorf("error: unknown catch option %s (use --ball, --hp or --status)", arg)
+		}
+	}
+
+	// name check, fall back to whatever turned up
+	if name == "" {
+		name = defaultName
+	}
+	if name == "" {
+		return "", catchOptions{}, fmt.Errorf("error: catch must take pokemon name as argument")
+	}
+	return name, opts, nil
+}
+
+// statusNames lists the statuses for error messages, sorted
+func statusNames() string {
+	names := make([]string, 0, len(catchStatuses))
+	for name := range catchStatuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// captureRoll runs the gen III/IV capture formula, returns whether it was caught and how many times the ball shook
+// a = (3*maxHP - 2*hp) * rate * ball / (3*maxHP) * status, caught outright if a >= 255
+// otherwise 4 shake checks, each passes if rand(65536) < 1048560 / (16711680/a)^(1/4)
+func captureRoll(cfg *config, captureRate int, ballBonus float64, opts catchOptions) (bool, int) {
+	// modified catch rate
+	hp := float64(opts.hpPercent) / 100
+	a := (3 - 2*hp) / 3 * float64(captureRate) * ballBonus * catchStatuses[opts.status]
+
+	// guaranteed catch check
+	if a >= 255 {
+		return true, 3
+	}
+
+	// shake threshold, never divide by zero for a 0 capture rate
+	a = math.Max(a, 1)
+	threshold := int(1048560 / math.Sqrt(math.Sqrt(16711680/a)))
+
+	// 4 shake checks, the ball visibly shakes (up to) 3 times and the 4th seals it
+	for shakes := 0; shakes < 4; shakes++ {
+		if cfg.intn(65536) >= threshold {
+			return false, shakes // broke free
+		}
+	}
+	return true, 3
+}
+
+// classicRoll is the original catch guess: harder the more base experience a pokemon gives
+// better balls multiply the chance (a master ball always wins)
+func classicRoll(cfg *config, baseExperience int, ballBonus float64) bool {
+	// calculate probability of catching using inverse proportion for simplicity
+	catchRate := 100.0 / (1.0 + float64(baseExperience)/60.0) // roll to beat, decreases with more base xp
+	// 60xp = 50%
+	// 180xp = 25%
+	// 1140xp = 5%
+	catchRate *= ballBonus // 1 for a plain pokeball
+
+	// determine catch success
+	catchRoll := cfg.intn(96)         // random roll from 0 to 95 (last int not incl)
+	return catchRoll < int(catchRate) // if we roll less than catch rate, this is true ie caught
+}
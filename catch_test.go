@@ -0,0 +1,77 @@
+// catch_test.go
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestParseCatchArgs(t *testing.T) {
+	cases := []struct {
+		input     string
+		wild      string // wild encounter name, if any
+		name      string
+		expected  catchOptions
+		expectErr string
+	}{
+		{input: "pikachu", name: "pikachu", expected: catchOptions{hpPercent: 100, status: "none", ball: "poke"}},
+		{input: "pikachu --hp 25% --status sleep", name: "pikachu", expected: catchOptions{hpPercent: 25, status: "sleep", ball: "poke"}},
+		{input: "--status burn pikachu", name: "pikachu", expected: catchOptions{hpPercent: 100, status: "burn", ball: "poke"}},
+		{input: "pikachu --ball great-ball", name: "pikachu", expected: catchOptions{hpPercent: 100, status: "none", ball: "great"}},
+		{input: "pikachu --ball ultra", name: "pikachu", expected: catchOptions{hpPercent: 100, status: "none", ball: "ultra"}},
+		{input: "pikachu --ball net", expectErr: "unknown ball net"},
+		{input: "pikachu --hp 0", expectErr: "--hp must be a percent"},
+		{input: "pikachu --status confused", expectErr: "unknown status confused"},
+		{input: "pikachu --hp", expectErr: "--hp needs a value"},
+		{input: "pikachu raichu", expectErr: "one pokemon name"},
+		{input: "--hp 50", expectErr: "must take pokemon name"},
+		{input: "--ball great", wild: "magikarp", name: "magikarp", expected: catchOptions{hpPercent: 100, status: "none", ball: "great"}},
+		{input: "pikachu", wild: "magikarp", name: "pikachu", expected: catchOptions{hpPercent: 100, status: "none", ball: "poke"}},
+	}
+
+	for _, c := range cases {
+		name, opts, err := parseCatchArgs(strings.Fields(c.input), c.wild)
+		if c.expectErr != "" {
+			if err == nil || !strings.Contains(err.Error(), c.expectErr) {
+				t.Errorf("parseCatchArgs(%q): expected error containing %q, got %v", c.input, c.expectErr, err)
+			}
+			continue
+		}
+		if err != nil || name != c.name || opts != c.expected {
+			t.Errorf("parseCatchArgs(%q): expected %s %+v, got %s %+v (%v)", c.input, c.name, c.expected, name, opts, err)
+		}
+	}
+}
+
+func TestCaptureRoll(t *testing.T) {
+	full := catchOptions{hpPercent: 100, status: "none"}
+	cases := []struct {
+		name        string
+		captureRate int
+		ball        string
+		opts        catchOptions
+		min, max    float64 // expected catch rate range over many throws
+	}{
+		{name: "mewtwo at full hp", captureRate: 3, ball: "poke", opts: full, min: 0, max: 0.02},
+		{name: "mewtwo master ball", captureRate: 3, ball: "master", opts: full, min: 1, max: 1},
+		{name: "magikarp at full hp", captureRate: 255, ball: "poke", opts: full, min: 0.28, max: 0.39},
+		{name: "magikarp ultra ball", captureRate: 255, ball: "ultra", opts: full, min: 0.6, max: 0.73},
+		{name: "magikarp weak and asleep", captureRate: 255, ball: "poke", opts: catchOptions{hpPercent: 1, status: "sleep"}, min: 1, max: 1},
+		{name: "lower hp helps", captureRate: 45, ball: "poke", opts: catchOptions{hpPercent: 10, status: "none"}, min: 0.17, max: 0.27},
+	}
+
+	for _, c := range cases {
+		cfg := &config{Rand: rand.New(rand.NewSource(1))}
+		caught := 0
+		for i := 0; i < 2000; i++ {
+			if ok, _ := captureRoll(cfg, c.captureRate, catchBalls[c.ball].bonus, c.opts); ok {
+				caught++
+			}
+		}
+		rate := float64(caught) / 2000
+		if rate < c.min || rate > c.max {
+			t.Errorf("%s: expected catch rate in [%.2f, %.2f], got %.3f", c.name, c.min, c.max, rate)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// encounter.go
+// for rolling random wild encounters (walk, fish, surf) in the explored area
+package main
+
+import (
+	"fmt"     // for errors
+	"sort"    // for listing rods
+	"strings" // for joining rod names
+
+	"github.com/PietPadda/pokedexcli/internal/pokeapi" // for encounter data
+)
+
+// encounter methods each wander command looks for, PokeAPI's encounter method names
+var walkMethods = []string{"walk"}
+var surfMethods = []string{"surf"}
+
+// defaultLevel is for pokemon with no encounter level data (made up areas, old saves)
+const defaultLevel = 5
+
+// fishing rods fish can take, each is its own encounter method
+var fishRods = map[string]string{
+	"old":   "old-rod",
+	"good":  "good-rod",
+	"super": "super-rod",
+}
+
+// wildEncounter is a pokemon that walk/fish/surf turned up, catch throws at it
+type wildEncounter struct {
+	name   string // pokemon name
+	level  int    // rolled from the slot's min/max levels
+	method string // encounter method it was met by (eg "surf")
+	area   string // location area it was met in
+}
+
+// encounterSlot is one way a pokemon can turn up, with its weight
+type encounterSlot struct {
+	name     string // pokemon name
+	method   string // encounter method
+	chance   int    // weight, percent
+	minLevel int    // lowest level
+	maxLevel int    // highest level
+}
+
+// encounterSlots collects every slot in area met by one of methods (nil = any method)
+// each pokemon uses the game version it's most common in, so versions don't stack up
+func encounterSlots(area *pokeapi.LocationAreaDetails, methods []string) []encounterSlot {
+	// nil ptr check
+	if area == nil {
+		return nil
+	}
+
+	// method lookup
+	wanted := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		wanted[method] = true
+	}
+
+	var slots []encounterSlot
+	for _, encounter := range area.PokemonEncounters {
+		var best []encounterSlot // best version's slots
+		bestChance := 0
+
+		for _, version := range encounter.VersionDetails {
+			var found []encounterSlot
+			total := 0
+			for _, detail := range version.EncounterDetails {
+				// method check
+				if (len(wanted) > 0 && !wanted[detail.Method.Name]) || detail.Chance <= 0 {
+					continue
+				}
+				found = append(found, encounterSlot{
+					name:     encounter.Pokemon.Name,
+					method:   detail.Method.Name,
+					chance:   detail.Chance,
+					minLevel: detail.MinLevel,
+					maxLevel: max(detail.MinLevel, detail.MaxLevel),
+				})
+				total += detail.Chance
+			}
+
+			// more common version check
+			if total > bestChance {
+				best, bestChance = found, total
+			}
+		}
+		slots = append(slots, best...)
+	}
+	return slots
+}
+
+// rollEncounter picks a slot weighted by chance and rolls a level in its range
+// false if nothing in area can be met by those methods
+func rollEncounter(cfg *config, area *pokeapi.LocationAreaDetails, methods []string) (wildEncounter, bool) {
+	slots := encounterSlots(area, methods)
+
+	// total weight
+	total := 0
+	for _, slot := range slots {
+		total += slot.chance
+	}
+
+	// nothing here check
+	if total == 0 {
+		return wildEncounter{}, false
+	}
+
+	// weighted pick, walk the slots until the roll runs out
+	roll := cfg.intn(total)
+	for _, slot := range slots {
+		if roll >= slot.chance {
+			roll -= slot.chance
+			continue
+		}
+		return wildEncounter{
+			name:   slot.name,
+			level:  slot.minLevel + cfg.intn(slot.maxLevel-slot.minLevel+1),
+			method: slot.method,
+			area:   area.Name,
+		}, true
+	}
+	return wildEncounter{}, false // unreachable, roll < total
+}
+
+// encounterLevel rolls a level for a pokemon met in area without walk/fish/surf (catch by name)
+// any way it can turn up there, weighted the same, or defaultLevel with no encounter data
+func encounterLevel(cfg *config, area string, encounter pokeapi.PokemonEncounter) int {
+	only := &pokeapi.LocationAreaDetails{Name: area, PokemonEncounters: []pokeapi.PokemonEncounter{encounter}}
+	if wild, ok := rollEncounter(cfg, only, nil); ok {
+		return wild.level
+	}
+	return defaultLevel
+}
+
+// fishMethods picks the rod(s) for fish, all of them if none is given
+func fishMethods(args []string) ([]string, error) {
+	// args check
+	if len(args) > 1 {
+		return nil, fmt.Errorf("error: fish takes at most one rod, got %d args", len(args))
+	}
+
+	// any rod
+	if len(args) == 0 {
+		methods := make([]string, 0, len(fishRods))
+		for _, method := range fishRods {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods) // stable for messages
+		return methods, nil
+	}
+
+	// one rod, "old" or "old-rod"
+	method, ok := fishRods[strings.TrimSuffix(args[0], "-rod")]
+	if !ok {
+		return nil, fmt.Errorf("error: unknown rod %s (use %s)", args[0], rodNames())
+	}
+	return []string{method}, nil
+}
+
+// rodNames lists the rods for error messages, sorted
+func rodNames() string {
+	names := make([]string, 0, len(fishRods))
+	for name := range fishRods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,92 @@
+// encounter_test.go
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/PietPadda/pokedexcli/internal/pokeapi"
+)
+
+// testEncounter builds an encounter met one way in one version
+func testEncounter(name, method string, chance, minLevel, maxLevel int) pokeapi.PokemonEncounter {
+	detail := pokeapi.EncounterDetail{Chance: chance, MinLevel: minLevel, MaxLevel: maxLevel}
+	detail.Method.Name = method
+	return pokeapi.PokemonEncounter{
+		Pokemon:        pokeapi.Pokemon{Name: name},
+		VersionDetails: []pokeapi.EncounterVersionDetails{{EncounterDetails: []pokeapi.EncounterDetail{detail}, MaxChance: chance}},
+	}
+}
+
+func TestRollEncounter(t *testing.T) {
+	area := &pokeapi.LocationAreaDetails{
+		Name: "test-area",
+		PokemonEncounters: []pokeapi.PokemonEncounter{
+			testEncounter("tentacool", "surf", 60, 20, 30),
+			testEncounter("wingull", "surf", 30, 20, 20),
+			testEncounter("pelipper", "surf", 10, 25, 25),
+			testEncounter("magikarp", "old-rod", 100, 3, 15),
+		},
+	}
+
+	cfg := &config{Rand: rand.New(rand.NewSource(1))}
+	counts := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		encounter, ok := rollEncounter(cfg, area, surfMethods)
+		if !ok {
+			t.Fatal("expected an encounter")
+		}
+		if encounter.method != "surf" || encounter.area != "test-area" {
+			t.Errorf("expected a surf encounter at test-area, got %+v", encounter)
+		}
+		if encounter.name == "tentacool" && (encounter.level < 20 || encounter.level > 30) {
+			t.Errorf("expected tentacool between Lv. 20 and 30, got %d", encounter.level)
+		}
+		counts[encounter.name]++
+	}
+
+	// weighted by chance, rod only pokemon never surface
+	expected := map[string][2]int{"tentacool": {1100, 1300}, "wingull": {500, 700}, "pelipper": {120, 280}, "magikarp": {0, 0}}
+	for name, bounds := range expected {
+		if counts[name] < bounds[0] || counts[name] > bounds[1] {
+			t.Errorf("%s: expected %d-%d of 2000 encounters, got %d", name, bounds[0], bounds[1], counts[name])
+		}
+	}
+
+	// nothing met by walking
+	if encounter, ok := rollEncounter(cfg, area, walkMethods); ok {
+		t.Errorf("expected no walk encounter, got %+v", encounter)
+	}
+}
+
+func TestFishMethods(t *testing.T) {
+	cases := []struct {
+		args      []string
+		expected  []string
+		expectErr bool
+	}{
+		{args: nil, expected: []string{"good-rod", "old-rod", "super-rod"}},
+		{args: []string{"super"}, expected: []string{"super-rod"}},
+		{args: []string{"old-rod"}, expected: []string{"old-rod"}},
+		{args: []string{"net"}, expectErr: true},
+		{args: []string{"old", "good"}, expectErr: true},
+	}
+
+	for _, c := range cases {
+		methods, err := fishMethods(c.args)
+		if (err != nil) != c.expectErr {
+			t.Errorf("fishMethods(%v): expected error %v, got %v", c.args, c.expectErr, err)
+			continue
+		}
+		if len(methods) != len(c.expected) {
+			t.Errorf("fishMethods(%v): expected %v, got %v", c.args, c.expected, methods)
+			continue
+		}
+		for i := range methods {
+			if methods[i] != c.expected[i] {
+				t.Errorf("fishMethods(%v): expected %v, got %v", c.args, c.expected, methods)
+				break
+			}
+		}
+	}
+}
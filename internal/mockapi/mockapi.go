@@ -0,0 +1,192 @@
+// internal/mockapi/mockapi.go
+// for a local PokeAPI stand-in server (integration tests and demos, no network needed)
+package mockapi
+
+import (
+	"bytes"         // for serving documents with ServeContent
+	"crypto/sha256" // for document ETags
+	"embed"         // for bundling the dataset
+	"encoding/hex"  // for ETag strings
+	"encoding/json" // for reading dataset ids and writing list pages
+	"fmt"
+	"io/fs"    // for walking the dataset
+	"net/http" // for the server
+	"path"     // for dataset file names
+	"sort"     // for ordering list pages by id
+	"strconv"  // for offset/limit params
+	"strings"  // for trimming .json
+	"time"     // for ServeContent (no modtime)
+)
+
+// bundled dataset, raw PokeAPI-shaped documents:
+// dataset/location-area/{name}.json, dataset/pokemon/{name}.json, dataset/pokemon-species/{name}.json,
+// dataset/item/{name}.json
+//
+//go:embed dataset
+var dataset embed.FS
+
+// api path prefix, so the client's base url is http://host/api/v2 just like the real thing
+const apiPrefix = "/api/v2"
+
+// default page size, same as PokeAPI
+const defaultLimit = 20
+
+// one resource type from the dataset, eg all location areas
+// lowercase (private) as its internal use only
+type resource struct {
+	name  string            // api name, eg "location-area"
+	docs  map[string][]byte // raw documents keyed by name AND id
+	order []namedResource   // list order (by id) for paginated list pages
+}
+
+// list page entry (NR) -- same shape as PokeAPI's named resources
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	id   int    // for sorting only
+}
+
+// list page (LP) -- same shape as PokeAPI's paginated lists
+type listPage struct {
+	Count    int             `json:"count"`
+	Next     *string         `json:"next"`
+	Previous *string         `json:"previous"`
+	Results  []namedResource `json:"results"`
+}
+
+// NewHandler returns an http.Handler serving the bundled dataset under /api/v2:
+// /location-area, /location-area/{name or id}, /pokemon, /pokemon/{name or id},
+// /pokemon-species, /pokemon-species/{name or id}, /item, /item/{name or id}
+func NewHandler() (http.Handler, error) {
+	mux := http.NewServeMux()
+
+	// load every resource type and register its routes
+	for _, name := range []string{"location-area", "pokemon", "pokemon-species", "item"} {
+		res, err := loadResource(name)
+
+		// load check
+		if err != nil {
+			return nil, err
+		}
+
+		mux.HandleFunc("GET "+apiPrefix+"/"+name, res.serveList)
+		mux.HandleFunc("GET "+apiPrefix+"/"+name+"/{$}", res.serveList)
+		mux.HandleFunc("GET "+apiPrefix+"/"+name+"/{name}", res.serveDetail)
+		mux.HandleFunc("GET "+apiPrefix+"/"+name+"/{name}/{$}", res.serveDetail)
+	}
+	return mux, nil
+}
+
+// loadResource reads dataset/{name}/*.json and indexes it by name and id
+func loadResource(name string) (*resource, error) {
+	res := &resource{name: name, docs: make(map[string][]byte)}
+
+	// list the resource dir
+	dir := path.Join("dataset", name)
+	files, err := fs.ReadDir(dataset, dir)
+
+	// read dir check
+	if err != nil {
+		return nil, fmt.Errorf("error reading dataset %s: %w", name, err)
+	}
+
+	// loop thru documents and index them
+	for _, file := range files {
+		raw, err := fs.ReadFile(dataset, path.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading dataset %s: %w", file.Name(), err)
+		}
+
+		// every PokeAPI resource has an id and a name
+		var meta struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return nil, fmt.Errorf("error decoding dataset %s: %w", file.Name(), err)
+		}
+
+		// file name must match, the client looks things up by name
+		if meta.Name != strings.TrimSuffix(file.Name(), ".json") {
+			return nil, fmt.Errorf("dataset %s has name %q", file.Name(), meta.Name)
+		}
+
+		res.docs[meta.Name] = raw
+		res.docs[strconv.Itoa(meta.ID)] = raw // lookup by id works too
+		res.order = append(res.order, namedResource{Name: meta.Name, id: meta.ID})
+	}
+
+	// list pages are ordered by id, like PokeAPI
+	sort.Slice(res.order, func(i, j int) bool { return res.order[i].id < res.order[j].id })
+	return res, nil
+}
+
+// serveDetail writes a single document, 404 if it's not in the dataset
+func (res *resource) serveDetail(w http.ResponseWriter, r *http.Request) {
+	raw, ok := res.docs[r.PathValue("name")]
+
+	// not found check
+	if !ok {
+		http.Error(w, "Not Found", http.StatusNotFound) // what PokeAPI sends too
+		return
+	}
+
+	// ETag from the content, ServeContent answers If-None-Match with a 304 (like PokeAPI's CDN)
+	sum := sha256.Sum256(raw)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+	w.Header().Set("Content-Type", "application/json")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(raw))
+}
+
+// serveList writes a paginated list page with next/previous links back to this server
+func (res *resource) serveList(w http.ResponseWriter, r *http.Request) {
+	// pagination params
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		http.Error(w, "invalid offset", http.StatusBadRequest)
+		return
+	}
+	limit, err := queryInt(r, "limit", defaultLimit)
+	if err != nil || limit < 1 {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	// links point back at whatever host the client used
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	listURL := scheme + "://" + r.Host + apiPrefix + "/" + res.name
+	pageURL := func(offset int) *string {
+		url := fmt.Sprintf("%s?offset=%d&limit=%d", listURL, offset, limit)
+		return &url
+	}
+
+	// build the page
+	count := len(res.order)
+	page := listPage{Count: count, Results: []namedResource{}}
+	for i := offset; i < count && i < offset+limit; i++ {
+		entry := res.order[i]
+		entry.URL = fmt.Sprintf("%s/%d/", listURL, entry.id)
+		page.Results = append(page.Results, entry)
+	}
+	if offset+limit < count {
+		page.Next = pageURL(offset + limit)
+	}
+	if offset > 0 {
+		page.Previous = pageURL(max(0, offset-limit))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// queryInt reads an int query param, def if missing
+func queryInt(r *http.Request, key string, def int) (int, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return def, nil
+	}
+	return strconv.Atoi(raw)
+}
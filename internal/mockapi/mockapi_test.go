@@ -0,0 +1,121 @@
+// mockapi_test.go
+package mockapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PietPadda/pokedexcli/internal/pokeapi"
+	"github.com/PietPadda/pokedexcli/internal/pokecache"
+)
+
+// newTestClient starts the mock server and returns a real client pointed at it
+func newTestClient(t *testing.T) *pokeapi.Client {
+	t.Helper()
+	handler, err := NewHandler()
+	if err != nil {
+		t.Fatalf("NewHandler unsuccesful: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	cache := pokecache.NewCache(time.Minute)
+	t.Cleanup(func() { cache.Close() }) // stop the reaper
+	client := pokeapi.NewClient(cache, pokeapi.WithBaseURL(server.URL+"/api/v2"))
+	return &client
+}
+
+func TestLocationAreaPagination(t *testing.T) {
+	client := newTestClient(t)
+
+	first, err := client.GetLocationAreas("")
+	if err != nil {
+		t.Fatalf("GetLocationAreas unsuccesful: %v", err)
+	}
+	if len(first.Results) != 20 || first.Previous != nil || first.Next == nil {
+		t.Fatalf("expected full first page with only a next link, got %d results", len(first.Results))
+	}
+	if first.Results[0].Name != "canalave-city-area" {
+		t.Errorf("expected areas ordered by id, got %s first", first.Results[0].Name)
+	}
+
+	// follow the next link, exactly like the map command does
+	second, err := client.GetLocationAreas(*first.Next)
+	if err != nil {
+		t.Fatalf("GetLocationAreas unsuccesful: %v", err)
+	}
+	if len(second.Results) != first.Count-20 || second.Next != nil || second.Previous == nil {
+		t.Fatalf("expected last page with only a previous link, got %d results", len(second.Results))
+	}
+
+	// and back again, like mapb
+	back, err := client.GetLocationAreas(*second.Previous)
+	if err != nil {
+		t.Fatalf("GetLocationAreas unsuccesful: %v", err)
+	}
+	if back.Results[0].Name != first.Results[0].Name {
+		t.Errorf("expected previous link to return the first page")
+	}
+}
+
+func TestDetails(t *testing.T) {
+	client := newTestClient(t)
+
+	area, err := client.GetLocationArea("canalave-city-area")
+	if err != nil {
+		t.Fatalf("GetLocationArea unsuccesful: %v", err)
+	}
+	if len(area.PokemonEncounters) == 0 {
+		t.Errorf("expected encounters at canalave-city-area")
+	}
+
+	// every encountered pokemon (and its species) must be in the dataset too, or catch would 404
+	for _, encounter := range area.PokemonEncounters {
+		pokemon, err := client.GetPokemonStats(encounter.Pokemon.Name)
+		if err != nil {
+			t.Errorf("GetPokemonStats(%s) unsuccesful: %v", encounter.Pokemon.Name, err)
+			continue
+		}
+		if species, err := client.GetPokemonSpecies(pokemon.SpeciesName()); err != nil || species.CaptureRate == 0 {
+			t.Errorf("GetPokemonSpecies(%s) unsuccesful: %+v (%v)", pokemon.SpeciesName(), species, err)
+		}
+	}
+
+	if _, err := client.GetPokemonStats("missingno"); !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	list, err := client.GetPokemonList()
+	if err != nil {
+		t.Fatalf("GetPokemonList unsuccesful: %v", err)
+	}
+	if len(list.Results) != list.Count {
+		t.Errorf("expected whole pokemon list in one page, got %d of %d", len(list.Results), list.Count)
+	}
+}
+
+func TestDetailETag(t *testing.T) {
+	handler, err := NewHandler()
+	if err != nil {
+		t.Fatalf("NewHandler unsuccesful: %v", err)
+	}
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, httptest.NewRequest("GET", "/api/v2/pokemon/pikachu", nil))
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d %q", first.Code, etag)
+	}
+
+	// same ETag back, nothing changed
+	req := httptest.NewRequest("GET", "/api/v2/pokemon/pikachu", nil)
+	req.Header.Set("If-None-Match", etag)
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, req)
+	if second.Code != http.StatusNotModified || second.Body.Len() != 0 {
+		t.Errorf("expected empty 304, got %d with %d bytes", second.Code, second.Body.Len())
+	}
+}
@@ -0,0 +1,212 @@
+// internal/pokeapi/box.go
+// for the individual pokemon we catch (the box), and the species we've seen
+package pokeapi // our internal package pokeapi
+
+import (
+	// standard Go libraries
+	"errors" // for ErrNotInBox
+	"fmt"    // for Errorf printing
+	"sort"   // for listing the box in id order
+	"time"   // for caught-at times
+)
+
+// ErrNotInBox is returned when no pokemon in the box has that id
+// capped (public) so callers can check it with errors.Is
+var ErrNotInBox = errors.New("no pokemon in the box with that id")
+
+// IVs are a caught pokemon's individual values, 0-31 per stat, rolled when it's caught
+type IVs struct {
+	HP             int `json:"hp"`
+	Attack         int `json:"attack"`
+	Defense        int `json:"defense"`
+	SpecialAttack  int `json:"special_attack"`
+	SpecialDefense int `json:"special_defense"`
+	Speed          int `json:"speed"`
+}
+
+// Total adds the ivs up (0-186), handy for comparing catches
+func (iv IVs) Total() int {
+	return iv.HP + iv.Attack + iv.Defense + iv.SpecialAttack + iv.SpecialDefense + iv.Speed
+}
+
+// CaughtPokemon is ONE pokemon we caught, catching a second pikachu gives a second one
+// the species' stats live in the Pokedex entry, this is what makes this one different
+type CaughtPokemon struct {
+	ID       int       `json:"id"`                 // unique in this box, handed out by the Pokedex
+	Species  string    `json:"species"`            // pokemon name, key of the Pokedex entry
+	Nickname string    `json:"nickname,omitempty"` // empty = none
+	Level    int       `json:"level"`              // level it was caught at
+	IVs      IVs       `json:"ivs"`                // individual values
+	Nature   string    `json:"nature"`             // eg "adamant"
+	CaughtAt time.Time `json:"caught_at"`          // when it was caught
+	Location string    `json:"location"`           // location area it was caught in
+}
+
+// DisplayName is the nickname, or the species if it hasn't got one
+func (c CaughtPokemon) DisplayName() string {
+	if c.Nickname != "" {
+		return c.Nickname
+	}
+	return c.Species
+}
+
+// pokedex seen function -- marks a species as seen (met in the wild), autosaves the first time
+func (p *Pokedex) PokemonSeen(name string) error {
+	// nil ptr check
+	if p == nil {
+		return fmt.Errorf("PokemonSeen called with nil receiver") // early return
+	}
+
+	// lock mutex before accessing map
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// already seen check, nothing to save
+	if p.seen[name] {
+		return nil
+	}
+	p.seen[name] = true
+	return p.autosaveLocked()
+}
+
+// pokedex get ALL seen names function -- every species seen, caught ones included
+func (p *Pokedex) PokemonGetAllSeen() ([]string, error) {
+	// nil ptr check
+	if p == nil {
+		return nil, fmt.Errorf("PokemonGetAllSeen called with nil receiver") // early return
+	}
+
+	// READ lock mutex before accessing map
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	names := make([]string, 0, len(p.seen))
+	for name := range p.seen {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// pokedex catch function -- records a caught pokemon
+// the species goes in the Pokedex (seen and caught), the individual goes in the box with a new id
+// returns the boxed pokemon (with its id), autosaves like PokemonAdd
+func (p *Pokedex) Catch(stats PokemonStats, caught CaughtPokemon) (CaughtPokemon, error) {
+	// nil ptr check
+	if p == nil {
+		return CaughtPokemon{}, fmt.Errorf("Catch called with nil receiver") // early return
+	}
+
+	// species check, the box is keyed back to the Pokedex by it
+	if caught.Species == "" {
+		return CaughtPokemon{}, fmt.Errorf("Catch called without a species") // early return
+	}
+
+	// caught just now check
+	if caught.CaughtAt.IsZero() {
+		caught.CaughtAt = time.Now()
+	}
+
+	// lock mutex before accessing maps
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// species entry
+	p.pokemon[caught.Species] = stats
+	p.seen[caught.Species] = true
+
+	// the individual, ids are never reused (even after a release)
+	p.nextID++
+	caught.ID = p.nextID
+	p.box[caught.ID] = caught
+
+	// autosave check (the catch is still in memory)
+	if err := p.autosaveLocked(); err != nil {
+		return caught, fmt.Errorf("pokemon caught but %w", err)
+	}
+	return caught, nil
+}
+
+// pokedex box function -- every caught pokemon, in id (catch) order
+func (p *Pokedex) Box() ([]CaughtPokemon, error) {
+	// nil ptr check
+	if p == nil {
+		return nil, fmt.Errorf("Box called with nil receiver") // early return
+	}
+
+	// READ lock mutex before accessing map
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.boxLocked(), nil
+}
+
+// pokedex box get function -- one caught pokemon by id
+func (p *Pokedex) BoxGet(id int) (CaughtPokemon, bool, error) {
+	// nil ptr check
+	if p == nil {
+		return CaughtPokemon{}, false, fmt.Errorf("BoxGet called with nil receiver") // early return
+	}
+
+	// READ lock mutex before accessing map
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	caught, ok := p.box[id]
+	return caught, ok, nil
+}
+
+// pokedex nickname function -- gives a caught pokemon a nickname (empty clears it)
+// returns an error wrapping ErrNotInBox if there's no such id
+func (p *Pokedex) Nickname(id int, nickname string) (CaughtPokemon, error) {
+	// nil ptr check
+	if p == nil {
+		return CaughtPokemon{}, fmt.Errorf("Nickname called with nil receiver") // early return
+	}
+
+	// lock mutex before accessing map
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// in box check
+	caught, ok := p.box[id]
+	if !ok {
+		return CaughtPokemon{}, fmt.Errorf("#%d: %w", id, ErrNotInBox)
+	}
+
+	caught.Nickname = nickname
+	p.box[id] = caught
+	return caught, p.autosaveLocked()
+}
+
+// pokedex release function -- lets a caught pokemon go
+// the species stays caught in the Pokedex, only the individual leaves the box
+// returns an error wrapping ErrNotInBox if there's no such id
+func (p *Pokedex) Release(id int) (CaughtPokemon, error) {
+	// nil ptr check
+	if p == nil {
+		return CaughtPokemon{}, fmt.Errorf("Release called with nil receiver") // early return
+	}
+
+	// lock mutex before accessing map
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// in box check
+	caught, ok := p.box[id]
+	if !ok {
+		return CaughtPokemon{}, fmt.Errorf("#%d: %w", id, ErrNotInBox)
+	}
+
+	delete(p.box, id)
+	return caught, p.autosaveLocked()
+}
+
+// boxLocked lists the box in id order, caller MUST hold p.mu (read or write)
+func (p *Pokedex) boxLocked() []CaughtPokemon {
+	box := make([]CaughtPokemon, 0, len(p.box))
+	for _, caught := range p.box {
+		box = append(box, caught)
+	}
+	sort.Slice(box, func(i, j int) bool { return box[i].ID < box[j].ID })
+	return box
+}
@@ -4,11 +4,12 @@ package pokeapi // our internal package pokeapi
 
 import (
 	// standard Go libraries
-	"encoding/json" // for unmarshalling json to Go readable
-	"fmt"           // for Errorf printing
-	"io"            // for reading raw json data
-	"net/http"      // for HTTP requests/responses
-	"sync"          // for Mutex on map concurrency safety
+	"context"  // for cancellable requests
+	"fmt"      // for Errorf printing
+	"net/http" // for HTTP requests/responses
+	"strings"  // for trimming base url
+	"sync"     // for Mutex on map concurrency safety
+	"time"     // for client timeout option
 
 	// internal packages
 	"github.com/PietPadda/pokedexcli/internal/pokecache" // our internal package pokecache
@@ -40,7 +41,45 @@ type LocationAreaDetails struct {
 
 // pokemon encounter array (PE) -- all fields exportable
 type PokemonEncounter struct {
-	Pokemon Pokemon `json:"pokemon"` // struct literal of SINGLE pokemon detail
+	VersionDetails []EncounterVersionDetails `json:"version_details"` // ARRAY of how it's encountered, one per game version
+	Pokemon        Pokemon                   `json:"pokemon"`         // struct literal of SINGLE pokemon detail
+}
+
+// encounter version details (EVD) -- all fields exportable
+type EncounterVersionDetails struct {
+	EncounterDetails []EncounterDetail `json:"encounter_details"` // ARRAY of encounter slots (method, levels, chance)
+	Version          struct {
+		Name string `json:"name"` // game version (eg "diamond")
+	} `json:"version"`
+	MaxChance int `json:"max_chance"` // total chance (percent) of meeting it in this version
+}
+
+// encounter detail (ED) -- one encounter slot, all fields exportable
+type EncounterDetail struct {
+	Method struct {
+		Name string `json:"name"` // how it's met (eg "walk", "surf", "old-rod")
+	} `json:"method"`
+	Chance   int `json:"chance"`    // percent chance of this slot
+	MinLevel int `json:"min_level"` // lowest level it's met at
+	MaxLevel int `json:"max_level"` // highest level it's met at
+}
+
+// Chance returns the percent chance (0-100) of meeting the pokemon here
+// the best game version's max_chance (or its slots added up if that's missing), capped at 100
+func (e PokemonEncounter) Chance() int {
+	best := 0
+	for _, version := range e.VersionDetails {
+		chance := version.MaxChance
+
+		// no max_chance check, add up the slots
+		if chance == 0 {
+			for _, detail := range version.EncounterDetails {
+				chance += detail.Chance
+			}
+		}
+		best = max(best, chance)
+	}
+	return min(best, 100)
 }
 
 // pokemon details array (PK) -- all fields exportable
@@ -54,6 +93,7 @@ type Pokemon struct {
 type PokemonStats struct {
 	Stats          []PokemonStat  `json:"stats"`           // ARRAY of pokemon stats
 	Types          []PokemonTypes `json:"types"`           // ARRAY of pokemon types
+	Species        Pokemon        `json:"species"`         // species name and url (for capture rate etc)
 	Name           string         `json:"name"`            // pokemon name (for storing in pokedex)
 	BaseExperience int            `json:"base_experience"` // pokemon base experience (for catch probability)
 	ID             int            `json:"id"`              // pokemon id (we use name, but can also use id)
@@ -61,6 +101,83 @@ type PokemonStats struct {
 	Weight         int            `json:"weight"`          // pokemon weight
 }
 
+// SpeciesName returns the species the pokemon belongs to, for GetPokemonSpecies
+// forms have their own name (eg "deoxys-attack"), saves from before species were kept don't have one
+func (p PokemonStats) SpeciesName() string {
+	if p.Species.Name != "" {
+		return p.Species.Name
+	}
+	return p.Name
+}
+
+// pokemon list response (PLR) -- every pokemon name, for "did you mean" suggestions
+type PokemonListResponse struct {
+	Results []Pokemon `json:"results"` // name and url array inside response (PK)
+	Count   int       `json:"count"`   // no of pokemon
+}
+
+// POKEMON SPECIES STRUCTS
+// pokemon species (PSp) -- data shared by every form of a pokemon, all fields exportable
+type PokemonSpecies struct {
+	GrowthRate struct {
+		Name string `json:"name"` // growth rate name (eg "medium-slow")
+	} `json:"growth_rate"`
+	Name        string `json:"name"`         // species name
+	ID          int    `json:"id"`           // species id (national dex number)
+	CaptureRate int    `json:"capture_rate"` // 3 (hardest) to 255 (easiest), for the catch formula
+	IsLegendary bool   `json:"is_legendary"` // legendary pokemon
+	IsMythical  bool   `json:"is_mythical"`  // mythical pokemon (event only in the games)
+}
+
+// ITEM STRUCTS
+// item (IT) -- item metadata (pokeballs etc), all fields exportable
+type Item struct {
+	EffectEntries []ItemEffect `json:"effect_entries"` // ARRAY of effect descriptions, one per language
+	Names         []ItemName   `json:"names"`          // ARRAY of display names, one per language
+	Sprites       struct {
+		Default string `json:"default"` // sprite image url
+	} `json:"sprites"`
+	Name string `json:"name"` // item name (eg "great-ball")
+	ID   int    `json:"id"`   // item id
+	Cost int    `json:"cost"` // price at a Poke Mart
+}
+
+// item effect (IE) -- all fields exportable
+type ItemEffect struct {
+	Language struct {
+		Name string `json:"name"` // language code (eg "en")
+	} `json:"language"`
+	ShortEffect string `json:"short_effect"` // one line description
+}
+
+// item name (IN) -- all fields exportable
+type ItemName struct {
+	Language struct {
+		Name string `json:"name"` // language code (eg "en")
+	} `json:"language"`
+	Name string `json:"name"` // display name (eg "Great Ball")
+}
+
+// DisplayName returns the english display name, or the api name if there isn't one
+func (i Item) DisplayName() string {
+	for _, name := range i.Names {
+		if name.Language.Name == "en" {
+			return name.Name
+		}
+	}
+	return i.Name
+}
+
+// Description returns the english short effect, empty if there isn't one
+func (i Item) Description() string {
+	for _, effect := range i.EffectEntries {
+		if effect.Language.Name == "en" {
+			return effect.ShortEffect
+		}
+	}
+	return ""
+}
+
 // CORE: remember to sort LARGEST to SMALLEST for memory efficiency!!
 
 // pokemon stat (Ps) -- all fields exportable
@@ -79,27 +196,146 @@ type PokemonTypes struct {
 }
 
 // CLIENT STRUCTS:
+// DefaultBaseURL is the public PokeAPI, used unless WithBaseURL says otherwise
+const DefaultBaseURL = "https://pokeapi.co/api/v2"
+
+// DefaultUserAgent is sent with every request unless WithUserAgent says otherwise
+const DefaultUserAgent = "pokedexcli"
+
+// how long a background refresh of a stale cache entry may take (all retries included)
+const revalidateTimeout = time.Minute
+
+// how long a shared in-flight request may take when its first caller set no deadline (all retries included)
+const flightTimeout = time.Minute
+
+// DefaultRequestTimeout is the per-request deadline unless WithRequestTimeout says otherwise
+// stops a hung PokeAPI request from freezing the REPL forever
+const DefaultRequestTimeout = 15 * time.Second
+
 // Client is the PokeAPI client
 type Client struct {
-	PokeapiClient http.Client      // holds HTTP client to make API requests
-	cache         *pokecache.Cache // cached entries to prevent unnecessary API requests
+	PokeapiClient  http.Client              // holds HTTP client to make API requests
+	cache          *pokecache.Cache         // cached entries to prevent unnecessary API requests
+	baseURL        string                   // api root, no trailing slash (self-hosted mirror, httptest server etc)
+	userAgent      string                   // User-Agent header sent with every request
+	requestTimeout time.Duration            // deadline for each HTTP request (0 = only the caller's ctx)
+	maxAttempts    int                      // tries per request before giving up (transient failures only)
+	backoffBase    time.Duration            // first retry delay, doubles each retry
+	backoffMax     time.Duration            // retry delay cap
+	limiter        *rateLimiter             // client-side rate limit for network requests (nil = unlimited)
+	flights        *flightGroup             // in-flight requests, so concurrent identical fetches share one
+	cacheTTLs      map[string]time.Duration // per endpoint cache ttl (eg "pokemon"), missing = cache interval
+	recordDir      string                   // fixtures dir to record into (empty = off), see WithRecorder
+	replayDir      string                   // fixtures dir to replay from (empty = off), see WithReplay
+}
+
+// API is everything the REPL needs from a PokeAPI client
+// *Client is the real one, pokeapitest.Fake serves fixtures for offline tests
+type API interface {
+	GetLocationAreasContext(ctx context.Context, pageURL string) (LocationAreaResponse, error)
+	GetLocationAreaContext(ctx context.Context, locationName string) (LocationAreaDetails, error)
+	GetPokemonStatsContext(ctx context.Context, pokemonName string) (PokemonStats, error)
+	GetPokemonListContext(ctx context.Context) (PokemonListResponse, error)
+	GetPokemonSpeciesContext(ctx context.Context, speciesName string) (PokemonSpecies, error)
+	GetItemContext(ctx context.Context, itemName string) (Item, error)
+}
+
+// compile time check that Client satisfies API
+var _ API = (*Client)(nil)
+
+// Option configures a Client in NewClient
+// capped (public) so main and tests can build their own
+type Option func(*Client)
+
+// WithBaseURL points the client at a different PokeAPI root (eg a mirror or httptest server)
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/") // endpoints add their own slash
+	}
+}
+
+// WithTransport swaps the HTTP transport (eg for fixtures or a proxy)
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.PokeapiClient.Transport = transport
+	}
+}
+
+// WithTimeout sets the overall HTTP client timeout (0 = no timeout)
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.PokeapiClient.Timeout = timeout
+	}
+}
+
+// WithRequestTimeout sets the deadline applied to each HTTP request (0 = no default deadline)
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = timeout
+	}
+}
+
+// WithCacheTTL caches responses from endpoint (eg "location-area", "pokemon") for ttl
+// instead of the cache's default interval, handy for data that never changes
+func WithCacheTTL(endpoint string, ttl time.Duration) Option {
+	return func(c *Client) {
+		// lazy init, most clients have no rules
+		if c.cacheTTLs == nil {
+			c.cacheTTLs = make(map[string]time.Duration)
+		}
+		c.cacheTTLs[strings.Trim(endpoint, "/")] = ttl
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
 }
 
 // NewClient creates a new PokeAPI client
-// now takes the cache for checking cached items
-func NewClient(cache *pokecache.Cache) Client { // init and returns a client
-	return Client{
-		PokeapiClient: http.Client{}, // init with a default HTTP client
-		cache:         cache,         // init with the cache
+// now takes the cache for checking cached items, plus any options
+func NewClient(cache *pokecache.Cache, opts ...Option) Client { // init and returns a client
+	client := Client{
+		PokeapiClient:  http.Client{},         // init with a default HTTP client
+		cache:          cache,                 // init with the cache
+		baseURL:        DefaultBaseURL,        // public pokeapi unless overridden
+		userAgent:      DefaultUserAgent,      // identify ourselves politely
+		requestTimeout: DefaultRequestTimeout, // never wait forever
+		maxAttempts:    DefaultMaxAttempts,    // retry transient failures
+		backoffBase:    defaultBackoffBase,
+		backoffMax:     defaultBackoffMax,
+		limiter:        newRateLimiter(DefaultRateLimit, DefaultRateBurst), // PokeAPI fair use
+		flights:        newFlightGroup(),                                   // request coalescing
+	}
+
+	// apply options in order
+	for _, opt := range opts {
+		opt(&client)
 	}
+
+	// fixture transports go on last, so no option can swap them out
+	client.useFixtures()
+	return client
+}
+
+// BaseURL returns the api root the client is pointed at
+func (c *Client) BaseURL() string {
+	return c.baseURL
 }
 
 // POKEDEX STRUCTS:
 // Pokedex is where the store and inspect the pokemon we catch
 // capped (public) for exposing to other packages
 type Pokedex struct {
-	pokemon map[string]PokemonStats // map of pokedex entries
-	mu      *sync.RWMutex           // mutex since maps aren't thread safe (must init in constructor as its ptr)
+	pokemon  map[string]PokemonStats // map of pokedex entries, species caught
+	seen     map[string]bool         // species seen in the wild (caught ones included)
+	box      map[int]CaughtPokemon   // every individual pokemon caught, by id (see box.go)
+	nextID   int                     // last box id handed out, ids are never reused
+	items    map[string]int          // inventory, item name -> count (see Inventory)
+	mu       *sync.RWMutex           // mutex since maps aren't thread safe (must init in constructor as its ptr)
+	savePath string                  // save file path for autosave (empty = autosave off)
 }
 
 // CORE: we use RWMutex here as we will frequently be reading from but, it STILL allows exclusive writing
@@ -111,6 +347,9 @@ type Pokedex struct {
 func NewPokedex() *Pokedex { // ptr = more efficient, no data copying when passing
 	pokedex := &Pokedex{
 		pokemon: make(map[string]PokemonStats), // inits new pokedex
+		seen:    make(map[string]bool),         // nothing seen yet
+		box:     make(map[int]CaughtPokemon),   // empty box
+		items:   StarterItems(),                // every trainer starts with some pokeballs
 		mu:      &sync.RWMutex{},               // inits the mutex (safe, avoid nil ptr deref)
 	}
 	return pokedex // return the pokedex
@@ -120,120 +359,39 @@ func NewPokedex() *Pokedex { // ptr = more efficient, no data copying when passi
 // takes a url request input, and outputs the location area and success/failure error
 // it's a method on the client (Go style "OOP")
 func (c *Client) GetLocationAreas(pageURL string) (LocationAreaResponse, error) {
+	return c.GetLocationAreasContext(context.Background(), pageURL)
+}
+
+// context-aware GetLocationAreas, the request is abandoned when ctx is cancelled
+func (c *Client) GetLocationAreasContext(ctx context.Context, pageURL string) (LocationAreaResponse, error) {
 	// nil ptr check
 	if c == nil {
 		return LocationAreaResponse{}, fmt.Errorf("GetLocationAreas called with nil receiver") // early return
 	} // runtime panic if try access ptr fields, no memory location!
 
 	// determine default url for locations
-	baseURL := "https://pokeapi.co/api/v2" // api url
-	resourceURL := "/location-area"        // resource url
-	fullURL := baseURL + resourceURL       // full url
+	baseURL := c.baseURL             // api url
+	resourceURL := "/location-area"  // resource url
+	fullURL := baseURL + resourceURL // full url
 
 	// handle empty input url
 	if pageURL == "" {
 		pageURL = fullURL // set url to fullURL
 	}
 
-	// cached entry call, store IF found and IF error
-	cachedEntries, ok, err := c.cache.CacheGet(pageURL) // if response already cached
-
-	// cache entries call check
-	if err != nil {
-		return LocationAreaResponse{}, fmt.Errorf("error getting cached entries: %w", err) // nil slice & error
-	}
-
-	// if cache entries found
-	if ok {
-		// first, create nil slice for external data response
-		var locationRes LocationAreaResponse
-
-		// then unmarshal
-		err := json.Unmarshal(cachedEntries, &locationRes)
-
-		// unmarshal to conv from raw json to go readable code
-		if err != nil {
-			return locationRes, fmt.Errorf("error unmarshalling json data: %w", err) // nil slice & error
-		}
-
-		// can now return the CACHED location area response from server as success
-		return locationRes, nil // nil error
-	}
-
-	// if not cached, need to make new HTTP GET request
-
-	// HTTP GET request using newrequest for more flexibility
-	req, err := http.NewRequest("GET", pageURL, nil) // GET request, so no response body
-
-	// HTTP request check
-	if err != nil {
-		return LocationAreaResponse{}, fmt.Errorf("error with HTTP request: %w", err) // empty slice & error
-	}
-
-	// modify GET request header (not required, but BEST GO PRACTICE)
-	req.Header.Set("Accept", "application/json") // expects json data as HTTP response
-	// CORE: "Content-Type" - sending TO server, "Accept" - response FROM server
-
-	// don’t create a new HTTP client
-	// client := &http.Client{}
-
-	// we no longer create a new client, but use the pokeapi httpClient below
-	// client do GET request
-	res, err := c.PokeapiClient.Do(req)
-
-	// client do GET check
-	if err != nil {
-		return LocationAreaResponse{}, fmt.Errorf("error client doing request: %w", err) // empty slice & error
-	}
-
-	// defer to close network connectoin after reading to prevent mem leak
-	defer res.Body.Close()
-
-	// get server response status code
-	statusCode := res.StatusCode // server response status code
-	resStatus := res.Status      // status code AND description
-
-	// status code check
-	if statusCode != http.StatusOK { // if not 200
-		return LocationAreaResponse{}, fmt.Errorf("error server response status code unsuccesful: %s", resStatus) // empty slice & status code w descr
-	}
-
-	// read server response body as raw json data,[]byte slice
-	body, err := io.ReadAll(res.Body)
-
-	// read body check
-	if err != nil {
-		return LocationAreaResponse{}, fmt.Errorf("error reading server response body: %w", err) // empty slice & error
-	}
-
-	// create nil slice for external data response
-	var locationRes LocationAreaResponse
-
-	// unmarshal to conv from raw json to go readable code
-	err = json.Unmarshal(body, &locationRes)
-
-	// unmarshal check
-	if err != nil {
-		return locationRes, fmt.Errorf("error unmarshalling json data: %w", err) // nil slice & error
-	}
-
-	// the http response is now unmarshalled, let's first add it to the cache for future reference!
-	err = c.cache.CacheAdd(pageURL, body) // add url as key to cache + body (the raw "data"), return error
-
-	// cache add check
-	if err != nil {
-		fmt.Printf("error adding to cache: %v\n", err) // HTTP request slice & error
-		// DON'T RETURN! we still want to continue with the actual HTTP response return, else nothing happens!
-	} // printf for FORMATTED print, println can't use %v verb!
-
-	// can now return the location area response from server as success
-	return locationRes, nil // nil error
+	// cached JSON fetch of the page
+	return fetch[LocationAreaResponse](ctx, c, pageURL)
 }
 
 // function to get details of a location using the PokeAPI client
 // takes a location name request input, and outputs the location area details and success/failure error
 // it's a method on the client (Go style "OOP")
 func (c *Client) GetLocationArea(locationName string) (LocationAreaDetails, error) {
+	return c.GetLocationAreaContext(context.Background(), locationName)
+}
+
+// context-aware GetLocationArea, the request is abandoned when ctx is cancelled
+func (c *Client) GetLocationAreaContext(ctx context.Context, locationName string) (LocationAreaDetails, error) {
 	// nil ptr check
 	if c == nil {
 		return LocationAreaDetails{}, fmt.Errorf("GetLocationArea called with nil receiver") // early return
@@ -245,106 +403,24 @@ func (c *Client) GetLocationArea(locationName string) (LocationAreaDetails, erro
 	}
 
 	// determine default url for locations
-	baseURL := "https://pokeapi.co/api/v2"         // api url
+	baseURL := c.baseURL                           // api url
 	endpointURL := "/location-area/"               // api endpoint url
 	resourceURL := locationName                    // location name
 	fullURL := baseURL + endpointURL + resourceURL // full url
 
-	// cached entry call, store IF found and IF error
-	cachedEntries, ok, err := c.cache.CacheGet(fullURL) // if response already cached
-
-	// cache entries call check
-	if err != nil {
-		return LocationAreaDetails{}, fmt.Errorf("error getting cached entries: %w", err) // nil slice & error
-	}
-
-	// if cache entries found
-	if ok {
-		// first, create nil slice for external data response
-		var locationRes LocationAreaDetails
-
-		// then unmarshal
-		err := json.Unmarshal(cachedEntries, &locationRes)
-
-		// unmarshal to conv from raw json to go readable code
-		if err != nil {
-			return locationRes, fmt.Errorf("error unmarshalling json data: %w", err) // nil slice & error
-		}
-
-		// can now return the CACHED location area response from server as success
-		return locationRes, nil // nil error
-	}
-
-	// if not cached, need to make new HTTP GET request
-
-	// HTTP GET request using newrequest for more flexibility
-	req, err := http.NewRequest("GET", fullURL, nil) // GET request, so no response body
-
-	// HTTP request check
-	if err != nil {
-		return LocationAreaDetails{}, fmt.Errorf("error with HTTP request: %w", err) // empty slice & error
-	}
-
-	// modify GET request header (not required, but BEST GO PRACTICE)
-	req.Header.Set("Accept", "application/json") // expects json data as HTTP response
-	// CORE: "Content-Type" - sending TO server, "Accept" - response FROM server
-
-	// client do GET request using pokeapi client
-	res, err := c.PokeapiClient.Do(req)
-
-	// client do GET check
-	if err != nil {
-		return LocationAreaDetails{}, fmt.Errorf("error client doing request: %w", err) // empty slice & error
-	}
-
-	// defer to close network connectoin after reading to prevent mem leak
-	defer res.Body.Close()
-
-	// get server response status code
-	statusCode := res.StatusCode // server response status code
-	resStatus := res.Status      // status code AND description
-
-	// status code check
-	if statusCode != http.StatusOK { // if not 200
-		return LocationAreaDetails{}, fmt.Errorf("error server response status code unsuccesful: %s", resStatus) // empty slice & status code w descr
-	}
-
-	// read server response body as raw json data,[]byte slice
-	body, err := io.ReadAll(res.Body)
-
-	// read body check
-	if err != nil {
-		return LocationAreaDetails{}, fmt.Errorf("error reading server response body: %w", err) // empty slice & error
-	}
-
-	// create nil slice for external data response
-	var locationRes LocationAreaDetails
-
-	// unmarshal to conv from raw json to go readable code
-	err = json.Unmarshal(body, &locationRes)
-
-	// unmarshal check
-	if err != nil {
-		return locationRes, fmt.Errorf("error unmarshalling json data: %w", err) // nil slice & error
-	}
-
-	// the http response is now unmarshalled, let's first add it to the cache for future reference!
-	err = c.cache.CacheAdd(fullURL, body) // add url as key to cache + body (the raw "data"), return error
-
-	// cache add check
-	if err != nil {
-		fmt.Printf("error adding to cache: %v\n", err) // HTTP request slice & error
-		// DON'T RETURN! we still want to continue with the actual HTTP response return, else nothing happens!
-	} // printf for FORMATTED print, println can't use %v verb!
-
-	// can now return the location area DETAILS response from server as success
-	return locationRes, nil // nil error
+	// cached JSON fetch of the location area
+	return fetch[LocationAreaDetails](ctx, c, fullURL)
 }
 
 // function to get stats of a pokemon using the PokeAPI client
 // takes a pokemon name request input, and outputs the pokemon stats and success/failure error
 // it's a method on the client (Go style "OOP")
 func (c *Client) GetPokemonStats(pokemonName string) (PokemonStats, error) {
+	return c.GetPokemonStatsContext(context.Background(), pokemonName)
+}
+
+// context-aware GetPokemonStats, the request is abandoned when ctx is cancelled
+func (c *Client) GetPokemonStatsContext(ctx context.Context, pokemonName string) (PokemonStats, error) {
 	// nil ptr check
 	if c == nil {
 		return PokemonStats{}, fmt.Errorf("GetPokemonStats called with nil receiver") // early return
@@ -356,104 +432,94 @@ func (c *Client) GetPokemonStats(pokemonName string) (PokemonStats, error) {
 	}
 
 	// determine default url for locations
-	baseURL := "https://pokeapi.co/api/v2"         // api url
+	baseURL := c.baseURL                           // api url
 	endpointURL := "/pokemon/"                     // api endpoint url
 	resourceURL := pokemonName                     // pokemon name
 	fullURL := baseURL + endpointURL + resourceURL // full url
 	// reference: GET https://pokeapi.co/api/v2/pokemon/{id or name}/
 
-	// cached entry call, store IF found and IF error
-	cachedEntries, ok, err := c.cache.CacheGet(fullURL) // if response already cached
-
-	// cache entries call check
-	if err != nil {
-		return PokemonStats{}, fmt.Errorf("error getting cached entries: %w", err) // nil slice & error
-	}
-
-	// if cache entries found
-	if ok {
-		// first, create nil slice for external data response
-		var pokemonRes PokemonStats
-
-		// then unmarshal
-		err := json.Unmarshal(cachedEntries, &pokemonRes)
-
-		// unmarshal to conv from raw json to go readable code
-		if err != nil {
-			return pokemonRes, fmt.Errorf("error unmarshalling json data: %w", err) // nil slice & error
-		}
-
-		// can now return the CACHED location area response from server as success
-		return pokemonRes, nil // nil error
-	}
-
-	// if not cached, need to make new HTTP GET request
-
-	// HTTP GET request using newrequest for more flexibility
-	req, err := http.NewRequest("GET", fullURL, nil) // GET request, so no response body
+	// cached JSON fetch of the pokemon
+	return fetch[PokemonStats](ctx, c, fullURL)
+}
 
-	// HTTP request check
-	if err != nil {
-		return PokemonStats{}, fmt.Errorf("error with HTTP request: %w", err) // empty slice & error
-	}
+// function to get the names of every pokemon using the PokeAPI client
+// outputs the full pokemon list (one big cached page) and success/failure error
+// it's a method on the client (Go style "OOP")
+func (c *Client) GetPokemonList() (PokemonListResponse, error) {
+	return c.GetPokemonListContext(context.Background())
+}
 
-	// modify GET request header (not required, but BEST GO PRACTICE)
-	req.Header.Set("Accept", "application/json") // expects json data as HTTP response
-	// CORE: "Content-Type" - sending TO server, "Accept" - response FROM server
+// context-aware GetPokemonList, the request is abandoned when ctx is cancelled
+func (c *Client) GetPokemonListContext(ctx context.Context) (PokemonListResponse, error) {
+	// nil ptr check
+	if c == nil {
+		return PokemonListResponse{}, fmt.Errorf("GetPokemonList called with nil receiver") // early return
+	} // runtime panic if try access ptr fields, no memory location!
 
-	// client do GET request using pokeapi client
-	res, err := c.PokeapiClient.Do(req)
+	// determine url for the whole list in one page
+	fullURL := c.baseURL + "/pokemon?limit=100000" // limit past the real count = everything
+	// reference: GET https://pokeapi.co/api/v2/pokemon?limit={n}
 
-	// client do GET check
-	if err != nil {
-		return PokemonStats{}, fmt.Errorf("error client doing request: %w", err) // empty slice & error
-	}
+	// cached JSON fetch of the list
+	return fetch[PokemonListResponse](ctx, c, fullURL)
+}
 
-	// defer to close network connectoin after reading to prevent mem leak
-	defer res.Body.Close()
+// function to get a pokemon species (capture rate, legendary etc) using the PokeAPI client
+// takes species name (PokemonStats.Species.Name, usually the same as the pokemon name)
+// returns the species as struct, error
+func (c *Client) GetPokemonSpecies(speciesName string) (PokemonSpecies, error) {
+	return c.GetPokemonSpeciesContext(context.Background(), speciesName)
+}
 
-	// get server response status code
-	statusCode := res.StatusCode // server response status code
-	resStatus := res.Status      // status code AND description
+// context-aware GetPokemonSpecies, the request is abandoned when ctx is cancelled
+func (c *Client) GetPokemonSpeciesContext(ctx context.Context, speciesName string) (PokemonSpecies, error) {
+	// nil ptr check
+	if c == nil {
+		return PokemonSpecies{}, fmt.Errorf("GetPokemonSpecies called with nil receiver") // early return
+	} // runtime panic if try access ptr fields, no memory location!
 
-	// status code check
-	if statusCode != http.StatusOK { // if not 200
-		return PokemonStats{}, fmt.Errorf("error server response status code unsuccesful: %s", resStatus) // empty slice & status code w descr
+	// species name check
+	if speciesName == "" {
+		return PokemonSpecies{}, fmt.Errorf("species name cannot be empty") // early return
 	}
 
-	// read server response body as raw json data,[]byte slice
-	body, err := io.ReadAll(res.Body)
+	// determine url for the species
+	fullURL := c.baseURL + "/pokemon-species/" + speciesName
+	// reference: GET https://pokeapi.co/api/v2/pokemon-species/{id or name}/
 
-	// read body check
-	if err != nil {
-		return PokemonStats{}, fmt.Errorf("error reading server response body: %w", err) // empty slice & error
-	}
+	// cached JSON fetch of the species
+	return fetch[PokemonSpecies](ctx, c, fullURL)
+}
 
-	// create nil slice for external data response
-	var pokemonRes PokemonStats
+// function to get an item's metadata (names, description, sprite) using the PokeAPI client
+// takes item name (eg "great-ball")
+// returns the item as struct, error
+func (c *Client) GetItem(itemName string) (Item, error) {
+	return c.GetItemContext(context.Background(), itemName)
+}
 
-	// unmarshal to conv from raw json to go readable code
-	err = json.Unmarshal(body, &pokemonRes)
+// context-aware GetItem, the request is abandoned when ctx is cancelled
+func (c *Client) GetItemContext(ctx context.Context, itemName string) (Item, error) {
+	// nil ptr check
+	if c == nil {
+		return Item{}, fmt.Errorf("GetItem called with nil receiver") // early return
+	} // runtime panic if try access ptr fields, no memory location!
 
-	// unmarshal check
-	if err != nil {
-		return pokemonRes, fmt.Errorf("error unmarshalling json data: %w", err) // nil slice & error
+	// item name check
+	if itemName == "" {
+		return Item{}, fmt.Errorf("item name cannot be empty") // early return
 	}
 
-	// the http response is now unmarshalled, let's first add it to the cache for future reference!
-	err = c.cache.CacheAdd(fullURL, body) // add url as key to cache + body (the raw "data"), return error
-
-	// cache add check
-	if err != nil {
-		fmt.Printf("error adding to cache: %v\n", err) // HTTP request slice & error
-		// DON'T RETURN! we still want to continue with the actual HTTP response return, else nothing happens!
-	} // printf for FORMATTED print, println can't use %v verb!
+	// determine url for the item
+	fullURL := c.baseURL + "/item/" + itemName
+	// reference: GET https://pokeapi.co/api/v2/item/{id or name}/
 
-	// can now return the location area DETAILS response from server as success
-	return pokemonRes, nil // nil error
+	// cached JSON fetch of the item
+	return fetch[Item](ctx, c, fullURL)
 }
 
 // pokedex add function -- adds a new entry to the pokedex
+// species entry only, Catch also puts the individual pokemon in the box
 // takes *Pokedex -- update the actual pokedex map NOT a copy
 // takes a URL-key:DATA-value pair as input
 func (p *Pokedex) PokemonAdd(name string, stats PokemonStats) error { // adds new pokemon entry
@@ -473,6 +539,12 @@ func (p *Pokedex) PokemonAdd(name string, stats PokemonStats) error { // adds ne
 	// update pokedex map by adding the pokemon
 	p.pokemon[pokemonName] = pokemonStats // fetches the whole struct and updates pokemon and stats
 	// p is ptr to pokedex, and pokemon is the map field. We set the map key to the name and its val is the stats!
+	p.seen[pokemonName] = true // can't catch what you haven't seen
+
+	// autosave check (still holding the lock, so save sees this add)
+	if err := p.autosaveLocked(); err != nil {
+		return fmt.Errorf("pokemon added but %w", err) // entry is still in memory
+	}
 
 	// successfully added new pokedex entry
 	return nil
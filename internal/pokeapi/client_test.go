@@ -0,0 +1,253 @@
+// client_test.go
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PietPadda/pokedexcli/internal/pokecache"
+)
+
+// newTestCache returns a cache that's closed (reaper stopped) when the test ends
+func newTestCache(t *testing.T) *pokecache.Cache {
+	cache := pokecache.NewCache(time.Minute)
+	t.Cleanup(func() { cache.Close() })
+	return cache
+}
+
+func TestClientOptions(t *testing.T) {
+	var gotPath, gotAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"name": "pikachu", "id": 25}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestCache(t),
+		WithBaseURL(server.URL+"/api/v2/"),
+		WithUserAgent("pokedexcli-test"),
+		WithTimeout(time.Second),
+	)
+
+	pokemon, err := client.GetPokemonStats("pikachu")
+	if err != nil {
+		t.Fatalf("GetPokemonStats unsuccesful: %v", err)
+	}
+	if pokemon.ID != 25 {
+		t.Errorf("expected id 25, got %d", pokemon.ID)
+	}
+	if gotPath != "/api/v2/pokemon/pikachu" {
+		t.Errorf("expected request to base url, got path %s", gotPath)
+	}
+	if gotAgent != "pokedexcli-test" {
+		t.Errorf("expected custom user agent, got %s", gotAgent)
+	}
+}
+
+func TestFetchUsesCache(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"name": "canalave-city-area", "pokemon_encounters": []}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestCache(t), WithBaseURL(server.URL))
+
+	for i := 0; i < 3; i++ {
+		area, err := client.GetLocationArea("canalave-city-area")
+		if err != nil {
+			t.Fatalf("GetLocationArea unsuccesful: %v", err)
+		}
+		if area.Name != "canalave-city-area" {
+			t.Errorf("expected decoded name, got %s", area.Name)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("expected 1 request, got %d", hits)
+	}
+}
+
+func TestFetchStatusError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	client := NewClient(newTestCache(t), WithBaseURL(server.URL))
+
+	if _, err := client.GetPokemonStats("missingno"); err == nil {
+		t.Errorf("expected error for 404")
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release // hang until the test is done
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(newTestCache(t),
+		WithBaseURL(server.URL),
+		WithRequestTimeout(10*time.Millisecond),
+		WithMaxAttempts(1),
+	)
+
+	_, err := client.GetPokemonStats("pikachu")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release // hang until the test is done
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(newTestCache(t), WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := client.GetLocationAreaContext(ctx, "canalave-city-area")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancelled, got %v", err)
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pokemon/missingno":
+			http.NotFound(w, r)
+		case "/pokemon/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`not json`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestCache(t), WithBaseURL(server.URL), WithMaxAttempts(1))
+
+	_, err := client.GetPokemonStats("missingno")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound || httpErr.URL != server.URL+"/pokemon/missingno" {
+		t.Errorf("expected *HTTPError with status and url, got %v", err)
+	}
+
+	_, err = client.GetPokemonStats("busy")
+	if !errors.Is(err, ErrRateLimited) || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected only ErrRateLimited, got %v", err)
+	}
+
+	_, err = client.GetPokemonStats("pikachu")
+	if !errors.Is(err, ErrDecode) {
+		t.Errorf("expected ErrDecode, got %v", err)
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// first response is id 1, every refresh after that is id 2
+		if hits.Add(1) == 1 {
+			w.Write([]byte(`{"name": "pikachu", "id": 1}`))
+			return
+		}
+		w.Write([]byte(`{"name": "pikachu", "id": 2}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Minute, pokecache.WithStaleWhileRevalidate(time.Minute))
+	defer cache.Close()
+	client := NewClient(cache, WithBaseURL(server.URL), WithCacheTTL("pokemon", 5*time.Millisecond))
+
+	client.GetPokemonStats("pikachu")
+	time.Sleep(10 * time.Millisecond) // entry is now stale
+
+	// stale entry is served straight away...
+	pokemon, err := client.GetPokemonStats("pikachu")
+	if err != nil || pokemon.ID != 1 {
+		t.Fatalf("expected stale id 1 immediately, got %d (%v)", pokemon.ID, err)
+	}
+
+	// ...and refreshed in the background
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		// refreshed entry has the same tiny ttl, so it may already be stale again
+		if body, ok, _, _ := cache.CacheGetStale(server.URL + "/pokemon/pikachu"); ok && strings.Contains(string(body), `"id": 2`) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("expected background refresh to update the cache, server saw %d requests", hits.Load())
+}
+
+func TestCacheTTLRules(t *testing.T) {
+	client := NewClient(nil,
+		WithBaseURL("https://pokeapi.co/api/v2"),
+		WithCacheTTL("location-area", time.Hour),
+		WithCacheTTL("/pokemon/", 2*time.Hour),
+	)
+	cases := map[string]time.Duration{
+		"https://pokeapi.co/api/v2/location-area":                    time.Hour,
+		"https://pokeapi.co/api/v2/location-area?offset=20&limit=20": time.Hour,
+		"https://pokeapi.co/api/v2/pokemon/pikachu":                  2 * time.Hour,
+		"https://pokeapi.co/api/v2/item/poke-ball":                   0,
+		"https://example.com/pokemon/pikachu":                        0,
+	}
+	for url, expected := range cases {
+		if actual := client.ttlFor(url); actual != expected {
+			t.Errorf("ttlFor(%s): expected %v, got %v", url, expected, actual)
+		}
+	}
+}
+
+func TestConditionalRefresh(t *testing.T) {
+	var full, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// our copy is current, no body needed
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"name": "pikachu", "id": 25}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Minute, pokecache.WithRevalidateWindow(time.Minute))
+	defer cache.Close()
+	client := NewClient(cache, WithBaseURL(server.URL), WithCacheTTL("pokemon", 5*time.Millisecond))
+
+	client.GetPokemonStats("pikachu")
+	time.Sleep(10 * time.Millisecond) // entry is now expired
+
+	// refresh is conditional, the 304 renews the cached copy
+	pokemon, err := client.GetPokemonStats("pikachu")
+	if err != nil || pokemon.ID != 25 {
+		t.Fatalf("expected cached id 25, got %d (%v)", pokemon.ID, err)
+	}
+	if full.Load() != 1 || notModified.Load() != 1 {
+		t.Errorf("expected 1 full response and 1 304, got %d and %d", full.Load(), notModified.Load())
+	}
+	if stats := cache.Stats(); stats.Renewals != 1 {
+		t.Errorf("expected 1 renewal, got %d", stats.Renewals)
+	}
+}
@@ -0,0 +1,41 @@
+// internal/pokeapi/errors.go
+// for typed errors callers can check with errors.Is / errors.As
+package pokeapi // our internal package pokeapi
+
+import (
+	// standard Go libraries
+	"errors"   // for sentinel errors
+	"fmt"      // for Sprintf
+	"net/http" // for status codes
+)
+
+// sentinel errors -- capped (public) so callers can check them with errors.Is
+var (
+	ErrNotFound    = errors.New("not found")                   // 404 from the api (typo'd name etc)
+	ErrRateLimited = errors.New("rate limited by the api")     // 429 from the api
+	ErrDecode      = errors.New("error decoding api response") // body wasn't the json we expected
+)
+
+// HTTPError is returned for any non-200 api response
+// use errors.As to get the status code and url
+type HTTPError struct {
+	StatusCode int    // eg 404
+	Status     string // eg "404 Not Found"
+	URL        string // requested url
+}
+
+// Error implements the error interface
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("error server response status code unsuccesful: %s (%s)", e.Status, e.URL)
+}
+
+// Is lets errors.Is(err, ErrNotFound) and errors.Is(err, ErrRateLimited) match on status code
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+// internal/pokeapi/fetch.go
+// for the shared cached JSON request path every endpoint goes through
+package pokeapi // our internal package pokeapi
+
+import (
+	// standard Go libraries
+	"context"       // for cancellable requests
+	"encoding/json" // for unmarshalling json to Go readable
+	"errors"        // for replay miss check
+	"fmt"           // for Errorf printing
+	"io"            // for reading raw json data
+	"net/http"      // for HTTP requests/responses
+	"strings"       // for matching ttl rules
+	"time"          // for Retry-After waits
+
+	// internal packages
+	"github.com/PietPadda/pokedexcli/internal/pokecache" // for ErrClosed
+)
+
+// response is what one successful GET came back with
+// lowercase (private) as its internal use only
+type response struct {
+	body        []byte               // raw json data (empty on a 304)
+	validators  pokecache.Validators // ETag/Last-Modified to revalidate with next time
+	notModified bool                 // 304, the cached copy is still good
+}
+
+// fetch gets url as JSON and decodes it into T, using the cache where possible
+// every Get* method goes through here, so retries, errors etc live in ONE place
+// generic funcs can't be methods in Go, so the client is passed in
+func fetch[T any](ctx context.Context, c *Client, url string) (T, error) {
+	// zero value of T for error returns
+	var zero T
+
+	// nil ptr check
+	if c == nil {
+		return zero, fmt.Errorf("fetch called with nil client") // early return
+	} // runtime panic if try access ptr fields, no memory location!
+
+	// cached entry call, store IF found, IF stale and IF error
+	// typed, so a repeat hit hands back the already decoded value (no unmarshalling)
+	res, ok, stale, err := pokecache.NewTypedCache(c.cache, decodeJSON[T]).GetStale(url) // if response already cached
+
+	// cache entries call check (decode errors are already descriptive)
+	if errors.Is(err, ErrDecode) {
+		return zero, err
+	}
+	if err != nil {
+		return zero, fmt.Errorf("error getting cached entries: %w", err)
+	}
+
+	// if cache entries found
+	if ok {
+		// stale check: answer now, refresh for next time
+		if stale {
+			revalidate[T](c, url)
+		}
+
+		// can now return the CACHED response as success
+		return res, nil
+	}
+
+	// if not cached, need to make new HTTP GET request
+	// concurrent callers for the same url share ONE request, ONE decode and ONE cache write
+	val, err := c.flights.do(ctx, url, fetchAndStore[T](c, url))
+
+	// request check
+	if err != nil {
+		return zero, err // already descriptive
+	}
+
+	// decoded once by fetchAndStore, shared like a cache hit (read-only)
+	res, ok = val.(T)
+	if !ok {
+		return zero, fmt.Errorf("in-flight request for %s decoded as %T, not %T", url, val, zero)
+	}
+
+	// can now return the response from server as success
+	return res, nil
+}
+
+// fetchAndStore returns the request func flights run for url:
+// GET it, decode it as T, then cache it with the endpoint's ttl, the decoded T is what waiters get
+// ctx is the flight's (detached) ctx, not any one caller's
+func fetchAndStore[T any](c *Client, url string) func(ctx context.Context) (any, error) {
+	return func(ctx context.Context) (any, error) {
+		// expired copy still around? send its validators, an unchanged response is then just a 304
+		cached, validators, hasCached, err := c.cache.CacheGetValidators(url)
+		if err != nil || !hasCached {
+			validators = pokecache.Validators{} // nothing to renew, plain GET
+		}
+
+		res, err := c.get(ctx, url, validators)
+
+		// request check
+		if err != nil {
+			return nil, err // already descriptive
+		}
+
+		// not modified check: renew the cached copy instead of downloading it again
+		// (only conditional requests get a 304, so we always have the copy we revalidated)
+		if res.notModified {
+			_, err := c.cache.CacheRenew(url, c.ttlFor(url)) // evicted since? fine, we still have the body
+
+			// renew check (closed = shutting down, nothing to report)
+			if err != nil && !errors.Is(err, pokecache.ErrClosed) {
+				fmt.Printf("error renewing cache entry: %v\n", err)
+			}
+			return decodeJSON[T](cached)
+		}
+		body := res.body
+
+		// make sure it decodes before caching, never cache junk
+		decoded, err := decodeJSON[T](body)
+		if err != nil {
+			return nil, err
+		}
+
+		// the http response is good, let's first add it to the cache for future reference!
+		// (decoded value too, so the next hit skips unmarshalling)
+		err = pokecache.NewTypedCache(c.cache, decodeJSON[T]).Add(url, body, decoded, c.ttlFor(url), res.validators)
+
+		// cache add check (closed = shutting down, nothing to report)
+		if err != nil && !errors.Is(err, pokecache.ErrClosed) {
+			fmt.Printf("error adding to cache: %v\n", err)
+			// DON'T RETURN! we still want to continue with the actual HTTP response return, else nothing happens!
+		}
+		return decoded, nil
+	}
+}
+
+// decodeJSON unmarshals raw json into T, failures are ErrDecode
+func decodeJSON[T any](raw []byte) (T, error) {
+	// unmarshal to conv from raw json to go readable code
+	var res T
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return res, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+	return res, nil
+}
+
+// revalidate refreshes a stale cache entry in the background
+// shares the in-flight request if one is already running for url, errors just keep the stale entry
+func revalidate[T any](c *Client, url string) {
+	go func() {
+		// own deadline, the caller's ctx is long gone by the time this finishes
+		ctx, cancel := context.WithTimeout(context.Background(), revalidateTimeout)
+		defer cancel()
+
+		c.flights.do(ctx, url, fetchAndStore[T](c, url))
+	}()
+}
+
+// ttlFor returns the cache ttl for url from the WithCacheTTL rules (0 = cache interval)
+// rules are keyed on the endpoint, the first path segment after the base url (eg "pokemon")
+func (c *Client) ttlFor(url string) time.Duration {
+	// no rules check
+	if len(c.cacheTTLs) == 0 {
+		return 0
+	}
+
+	// strip base url, query and leading slash: ".../location-area?offset=20" -> "location-area"
+	path, ok := strings.CutPrefix(url, c.baseURL)
+	if !ok {
+		return 0 // not one of ours
+	}
+	path, _, _ = strings.Cut(path, "?")
+	endpoint, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+	return c.cacheTTLs[endpoint]
+}
+
+// getOnce does a single HTTP GET attempt for url
+// non-200 responses are errors (except a 304 to a conditional request), retry reports whether trying again could help
+// retryAfter is the server's Retry-After hint (0 if none)
+func (c *Client) getOnce(ctx context.Context, url string, validators pokecache.Validators) (res response, retry bool, retryAfter time.Duration, err error) {
+	// default deadline so a hung server can't block forever (per attempt)
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel() // release timer once the body is read
+	}
+
+	// HTTP GET request using newrequest for more flexibility
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil) // GET request, so no response body
+
+	// HTTP request check
+	if err != nil {
+		return response{}, false, 0, fmt.Errorf("error with HTTP request: %w", err) // bad url, never retry
+	}
+
+	// modify GET request header (not required, but BEST GO PRACTICE)
+	req.Header.Set("Accept", "application/json") // expects json data as HTTP response
+	// CORE: "Content-Type" - sending TO server, "Accept" - response FROM server
+	req.Header.Set("User-Agent", c.userAgent) // identify ourselves to the api
+
+	// conditional request, the server answers 304 (no body) if our copy is still current
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+
+	// client do GET request using pokeapi client
+	httpRes, err := c.PokeapiClient.Do(req)
+
+	// client do GET check (connection reset, per attempt timeout etc are transient)
+	if err != nil {
+		retry := !errors.Is(err, ErrNoFixture) // replay misses never fix themselves
+		return response{}, retry, 0, fmt.Errorf("error client doing request: %w", err)
+	}
+
+	// defer to close network connectoin after reading to prevent mem leak
+	defer httpRes.Body.Close()
+
+	// not modified check (only valid if we asked conditionally)
+	if httpRes.StatusCode == http.StatusNotModified && !validators.IsZero() {
+		return response{notModified: true}, false, 0, nil
+	}
+
+	// status code check
+	if httpRes.StatusCode != http.StatusOK { // if not 200
+		// 429 and 5xx are worth retrying, anything else (404 etc) fails fast
+		transient := httpRes.StatusCode == http.StatusTooManyRequests || httpRes.StatusCode >= 500
+		wait := parseRetryAfter(httpRes.Header.Get("Retry-After"), time.Now())
+		return response{}, transient, wait, &HTTPError{StatusCode: httpRes.StatusCode, Status: httpRes.Status, URL: url}
+	}
+
+	// read server response body as raw json data,[]byte slice
+	body, err := io.ReadAll(httpRes.Body)
+
+	// read body check (cut off mid body, also transient)
+	if err != nil {
+		return response{}, true, 0, fmt.Errorf("error reading server response body: %w", err)
+	}
+
+	// keep the validators so the next refresh can be conditional
+	res = response{
+		body: body,
+		validators: pokecache.Validators{
+			ETag:         httpRes.Header.Get("ETag"),
+			LastModified: httpRes.Header.Get("Last-Modified"),
+		},
+	}
+	return res, false, 0, nil
+}
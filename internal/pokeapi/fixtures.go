@@ -0,0 +1,176 @@
+// internal/pokeapi/fixtures.go
+// for recording real PokeAPI responses and replaying them offline (CI, airplane mode)
+package pokeapi // our internal package pokeapi
+
+import (
+	// standard Go libraries
+	"bytes"         // for re-reading recorded bodies
+	"crypto/sha256" // for short unique fixture names
+	"encoding/hex"  // for hex hash suffix
+	"encoding/json" // for fixture file format
+	"errors"        // for sentinel errors
+	"fmt"           // for Errorf printing
+	"io"            // for reading bodies
+	"io/fs"         // for missing fixture check
+	"net/http"      // for RoundTripper
+	"os"            // for fixture files
+	"path/filepath" // for fixture paths
+	"regexp"        // for readable fixture names
+)
+
+// ErrNoFixture is returned in replay mode for a url that was never recorded
+// never retried, replay should fail loudly so missing fixtures get noticed
+var ErrNoFixture = errors.New("no recorded fixture for url")
+
+// recorded response (RR) -- one file per url in the fixtures dir
+type recordedResponse struct {
+	URL        string      `json:"url"`     // full url that was requested
+	StatusCode int         `json:"status"`  // eg 200, 404
+	Header     http.Header `json:"headers"` // response headers
+	Body       string      `json:"body"`    // raw response body
+}
+
+// anything that isn't safe in a file name
+var unsafeFixtureChars = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+
+// fixtureName returns the fixture file for a request url
+// keyed on path + query only, so fixtures recorded against pokeapi.co replay against any base url
+// readable slug for humans + short hash so different queries never collide
+func fixtureName(req *http.Request) string {
+	key := req.URL.RequestURI()       // eg /api/v2/location-area?offset=20&limit=20
+	sum := sha256.Sum256([]byte(key)) // unique part
+	slug := unsafeFixtureChars.ReplaceAllString(key, "_")
+	if len(slug) > 80 {
+		slug = slug[:80] // keep file names sane
+	}
+	return slug + "-" + hex.EncodeToString(sum[:4]) + ".json"
+}
+
+// WithRecorder records every real response (url, status, headers, body) into dir
+// the recorder wraps the final transport (WithTransport or the default), in any option order
+func WithRecorder(dir string) Option {
+	return func(c *Client) {
+		c.recordDir = dir
+	}
+}
+
+// WithReplay serves responses ONLY from fixtures in dir, unknown urls fail with ErrNoFixture
+// also turns off rate limiting, there's no server to be polite to (WithTransport/WithRateLimit can't undo it)
+func WithReplay(dir string) Option {
+	return func(c *Client) {
+		c.replayDir = dir
+	}
+}
+
+// useFixtures builds the transport chain for record/replay, NewClient calls it after every option has run
+// replay replaces the transport, the recorder is outermost so it sees exactly what the client gets
+func (c *Client) useFixtures() {
+	// replay check
+	if c.replayDir != "" {
+		c.PokeapiClient.Transport = &replayTransport{dir: c.replayDir}
+		c.limiter = nil
+	}
+
+	// record check, wrap whatever transport is set
+	if c.recordDir != "" {
+		next := c.PokeapiClient.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		c.PokeapiClient.Transport = &recordTransport{next: next, dir: c.recordDir}
+	}
+}
+
+// transport that records responses from next
+// lowercase (private) as its internal use only
+type recordTransport struct {
+	next http.RoundTripper // real transport
+	dir  string            // fixtures dir
+}
+
+// RoundTrip implements http.RoundTripper, records then hands back an unread copy of the body
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// do the real request
+	res, err := t.next.RoundTrip(req)
+
+	// transport check, nothing to record
+	if err != nil {
+		return nil, err
+	}
+
+	// not modified check, the 200 recorded earlier is still the right fixture (don't clobber it with an empty body)
+	if res.StatusCode == http.StatusNotModified {
+		return res, nil
+	}
+
+	// read the body so we can both save and return it
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+
+	// read body check
+	if err != nil {
+		return nil, fmt.Errorf("error reading response to record: %w", err)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body)) // caller still gets the full body
+
+	// record it
+	recorded := recordedResponse{
+		URL:        req.URL.String(),
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body:       string(body),
+	}
+	raw, err := json.MarshalIndent(recorded, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(t.dir, 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(filepath.Join(t.dir, fixtureName(req)), raw, 0o644)
+	}
+
+	// record check, recording must not silently miss responses
+	if err != nil {
+		return nil, fmt.Errorf("error recording fixture: %w", err)
+	}
+
+	return res, nil
+}
+
+// transport that only serves recorded fixtures
+// lowercase (private) as its internal use only
+type replayTransport struct {
+	dir string // fixtures dir
+}
+
+// RoundTrip implements http.RoundTripper, never touches the network
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// read the fixture for this url
+	raw, err := os.ReadFile(filepath.Join(t.dir, fixtureName(req)))
+
+	// missing fixture check, fail loudly
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s (in %s)", ErrNoFixture, req.URL.RequestURI(), t.dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading fixture: %w", err)
+	}
+
+	// decode the fixture
+	var recorded recordedResponse
+	if err := json.Unmarshal(raw, &recorded); err != nil {
+		return nil, fmt.Errorf("error decoding fixture for %s: %w", req.URL.RequestURI(), err)
+	}
+
+	// rebuild the response
+	return &http.Response{
+		StatusCode:    recorded.StatusCode,
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(recorded.Body))),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
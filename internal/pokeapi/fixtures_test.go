@@ -0,0 +1,90 @@
+// fixtures_test.go
+package pokeapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/pokemon/missingno" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Test", "recorded")
+		w.Write([]byte(`{"name": "pikachu", "id": 25}`))
+	}))
+
+	// record against the live (test) server
+	recorder := NewClient(newTestCache(t), WithBaseURL(server.URL+"/api/v2"), WithRecorder(dir))
+	if _, err := recorder.GetPokemonStats("pikachu"); err != nil {
+		t.Fatalf("GetPokemonStats unsuccesful: %v", err)
+	}
+	if _, err := recorder.GetPokemonStats("missingno"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound while recording, got %v", err)
+	}
+	server.Close() // no network from here on
+
+	// replay against a different base url, fixtures are keyed on path only
+	replayer := NewClient(newTestCache(t), WithBaseURL("https://pokeapi.co/api/v2"), WithReplay(dir))
+	pokemon, err := replayer.GetPokemonStats("pikachu")
+	if err != nil {
+		t.Fatalf("replay unsuccesful: %v", err)
+	}
+	if pokemon.ID != 25 {
+		t.Errorf("expected id 25, got %d", pokemon.ID)
+	}
+	if _, err := replayer.GetPokemonStats("missingno"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected recorded 404 to replay as ErrNotFound, got %v", err)
+	}
+
+	// unknown url fails loudly, and only once (no retries)
+	if _, err := replayer.GetPokemonStats("mewtwo"); !errors.Is(err, ErrNoFixture) {
+		t.Errorf("expected ErrNoFixture, got %v", err)
+	}
+}
+
+// countingTransport counts requests on their way to next
+type countingTransport struct {
+	next  http.RoundTripper
+	count int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.count++
+	return t.next.RoundTrip(req)
+}
+
+func TestFixturesIgnoreOptionOrder(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "pikachu", "id": 25}`))
+	}))
+	defer server.Close()
+
+	// WithTransport AFTER WithRecorder still gets recorded
+	transport := &countingTransport{next: http.DefaultTransport}
+	recorder := NewClient(newTestCache(t), WithBaseURL(server.URL), WithRecorder(dir), WithTransport(transport))
+	if _, err := recorder.GetPokemonStats("pikachu"); err != nil {
+		t.Fatalf("GetPokemonStats unsuccesful: %v", err)
+	}
+	files, _ := os.ReadDir(dir)
+	if transport.count != 1 || len(files) != 1 {
+		t.Errorf("expected 1 request through the transport and 1 fixture, got %d and %d", transport.count, len(files))
+	}
+
+	// WithTransport AFTER WithReplay doesn't turn replay off
+	unused := &countingTransport{next: http.DefaultTransport}
+	replayer := NewClient(newTestCache(t), WithBaseURL(server.URL), WithReplay(dir), WithTransport(unused), WithRateLimit(1, 1))
+	if _, err := replayer.GetPokemonStats("mewtwo"); !errors.Is(err, ErrNoFixture) {
+		t.Errorf("expected ErrNoFixture, got %v", err)
+	}
+	if unused.count != 0 || replayer.limiter != nil {
+		t.Errorf("expected replay to skip the transport and rate limit, got %d requests, limiter %v", unused.count, replayer.limiter)
+	}
+}
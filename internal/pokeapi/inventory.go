@@ -0,0 +1,123 @@
+// internal/pokeapi/inventory.go
+// for the player's bag of items (pokeballs), saved along with the Pokedex
+package pokeapi // our internal package pokeapi
+
+import (
+	// standard Go libraries
+	"errors" // for ErrNoItem
+	"fmt"    // for Errorf printing
+)
+
+// pokeball item names, exactly as PokeAPI's /item endpoint calls them
+const (
+	ItemPokeBall   = "poke-ball"
+	ItemGreatBall  = "great-ball"
+	ItemUltraBall  = "ultra-ball"
+	ItemMasterBall = "master-ball"
+)
+
+// ErrNoItem is returned by Inventory.Use when there are none of that item left
+// capped (public) so callers can check it with errors.Is
+var ErrNoItem = errors.New("none left")
+
+// StarterItems returns what a new Pokedex starts with (saves from before inventories get this too)
+// nothing refills them, a thrown ball is gone for good
+func StarterItems() map[string]int {
+	return map[string]int{
+		ItemPokeBall:   20,
+		ItemGreatBall:  10,
+		ItemUltraBall:  5,
+		ItemMasterBall: 1,
+	}
+}
+
+// Inventory is the player's bag, item name -> count
+// it lives inside the Pokedex (same lock, same save file, same autosave), this is just a handle to it
+type Inventory struct {
+	pokedex *Pokedex // owner of the items map
+}
+
+// pokedex inventory function -- returns the handle to the pokedex's items
+func (p *Pokedex) Inventory() *Inventory {
+	return &Inventory{pokedex: p}
+}
+
+// inventory count function -- how many of an item we have (0 if none)
+func (inv *Inventory) Count(name string) int {
+	// nil ptr check
+	if inv == nil || inv.pokedex == nil {
+		return 0 // nothing in a bag that doesn't exist
+	}
+
+	// READ lock mutex before accessing map
+	inv.pokedex.mu.RLock()
+	defer inv.pokedex.mu.RUnlock()
+
+	return inv.pokedex.items[name]
+}
+
+// inventory items function -- returns a copy of every item and its count
+func (inv *Inventory) Items() map[string]int {
+	// nil ptr check
+	if inv == nil || inv.pokedex == nil {
+		return map[string]int{}
+	}
+
+	// READ lock mutex before accessing map
+	inv.pokedex.mu.RLock()
+	defer inv.pokedex.mu.RUnlock()
+
+	// copy so callers can't change the bag behind our back
+	items := make(map[string]int, len(inv.pokedex.items))
+	for name, count := range inv.pokedex.items {
+		items[name] = count
+	}
+	return items
+}
+
+// inventory add function -- adds n of an item, autosaves like PokemonAdd
+func (inv *Inventory) Add(name string, n int) error {
+	// nil ptr check
+	if inv == nil || inv.pokedex == nil {
+		return fmt.Errorf("Add called with nil inventory") // early return
+	}
+
+	// count check
+	if n <= 0 {
+		return fmt.Errorf("can't add %d %s", n, name) // early return
+	}
+
+	// lock mutex before accessing map
+	p := inv.pokedex
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.items[name] += n
+	return p.autosaveLocked()
+}
+
+// inventory use function -- takes one of an item out of the bag
+// returns an error wrapping ErrNoItem if there are none left
+func (inv *Inventory) Use(name string) error {
+	// nil ptr check
+	if inv == nil || inv.pokedex == nil {
+		return fmt.Errorf("Use called with nil inventory") // early return
+	}
+
+	// lock mutex before accessing map
+	p := inv.pokedex
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// none left check
+	if p.items[name] <= 0 {
+		return fmt.Errorf("%s: %w", name, ErrNoItem)
+	}
+
+	// use one, drop the entry once it's empty
+	p.items[name]--
+	if p.items[name] == 0 {
+		delete(p.items, name)
+	}
+	return p.autosaveLocked()
+}
@@ -0,0 +1,128 @@
+// internal/pokeapi/pokeapitest/fake.go
+// for testing PokeAPI consumers offline, serves fixture JSON instead of the network
+package pokeapitest
+
+import (
+	"context"       // to match pokeapi.API and honour cancellation
+	"embed"         // for bundling the fixtures
+	"encoding/json" // for decoding fixtures
+	"errors"        // for missing fixture check
+	"fmt"
+	"io/fs"    // for reading fixtures from any filesystem
+	"net/http" // for status codes
+	"net/url"  // for parsing page urls
+	"strconv"  // for offset parsing
+
+	"github.com/PietPadda/pokedexcli/internal/pokeapi" // the api we fake
+)
+
+// bundled fixture files, laid out like the api paths:
+// location-area/offset-{n}.json, location-area/{name}.json, pokemon/{name}.json, pokemon-species/{name}.json, item/{name}.json, pokemon-list.json
+//
+//go:embed fixtures
+var fixtures embed.FS
+
+// Fake is an offline pokeapi.API backed by fixture JSON files
+// missing fixtures behave like a 404 (pokeapi.ErrNotFound)
+type Fake struct {
+	fsys fs.FS // fixture files
+}
+
+// compile time check that Fake satisfies pokeapi.API
+var _ pokeapi.API = (*Fake)(nil)
+
+// New creates a Fake serving the bundled fixtures
+func New() *Fake {
+	sub, err := fs.Sub(fixtures, "fixtures")
+	if err != nil {
+		panic(err) // embedded dir always exists, can only fail if the build is broken
+	}
+	return &Fake{fsys: sub}
+}
+
+// NewFromFS creates a Fake serving fixtures from fsys (same layout as the bundled ones)
+func NewFromFS(fsys fs.FS) *Fake {
+	return &Fake{fsys: fsys}
+}
+
+// GetLocationAreasContext serves location-area/offset-{n}.json, n from the page url (0 if empty)
+func (f *Fake) GetLocationAreasContext(ctx context.Context, pageURL string) (pokeapi.LocationAreaResponse, error) {
+	// page offset, first page by default
+	offset := 0
+	if pageURL != "" {
+		parsed, err := url.Parse(pageURL)
+		if err != nil {
+			return pokeapi.LocationAreaResponse{}, fmt.Errorf("error parsing page url: %w", err)
+		}
+		if raw := parsed.Query().Get("offset"); raw != "" {
+			offset, err = strconv.Atoi(raw)
+			if err != nil {
+				return pokeapi.LocationAreaResponse{}, fmt.Errorf("error parsing page offset: %w", err)
+			}
+		}
+	}
+
+	var res pokeapi.LocationAreaResponse
+	err := f.load(ctx, fmt.Sprintf("location-area/offset-%d.json", offset), &res)
+	return res, err
+}
+
+// GetLocationAreaContext serves location-area/{name}.json
+func (f *Fake) GetLocationAreaContext(ctx context.Context, locationName string) (pokeapi.LocationAreaDetails, error) {
+	var res pokeapi.LocationAreaDetails
+	err := f.load(ctx, "location-area/"+locationName+".json", &res)
+	return res, err
+}
+
+// GetPokemonStatsContext serves pokemon/{name}.json
+func (f *Fake) GetPokemonStatsContext(ctx context.Context, pokemonName string) (pokeapi.PokemonStats, error) {
+	var res pokeapi.PokemonStats
+	err := f.load(ctx, "pokemon/"+pokemonName+".json", &res)
+	return res, err
+}
+
+// GetPokemonListContext serves pokemon-list.json
+func (f *Fake) GetPokemonListContext(ctx context.Context) (pokeapi.PokemonListResponse, error) {
+	var res pokeapi.PokemonListResponse
+	err := f.load(ctx, "pokemon-list.json", &res)
+	return res, err
+}
+
+// GetPokemonSpeciesContext serves pokemon-species/{name}.json
+func (f *Fake) GetPokemonSpeciesContext(ctx context.Context, speciesName string) (pokeapi.PokemonSpecies, error) {
+	var res pokeapi.PokemonSpecies
+	err := f.load(ctx, "pokemon-species/"+speciesName+".json", &res)
+	return res, err
+}
+
+// GetItemContext serves item/{name}.json
+func (f *Fake) GetItemContext(ctx context.Context, itemName string) (pokeapi.Item, error) {
+	var res pokeapi.Item
+	err := f.load(ctx, "item/"+itemName+".json", &res)
+	return res, err
+}
+
+// load decodes the fixture at name into v, the same way the real client reports errors
+func (f *Fake) load(ctx context.Context, name string, v any) error {
+	// cancelled check, like a real request would
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// read the fixture
+	raw, err := fs.ReadFile(f.fsys, name)
+
+	// missing fixture = 404
+	if errors.Is(err, fs.ErrNotExist) {
+		return &pokeapi.HTTPError{StatusCode: http.StatusNotFound, Status: "404 Not Found", URL: name}
+	}
+	if err != nil {
+		return fmt.Errorf("error reading fixture %s: %w", name, err)
+	}
+
+	// decode the fixture
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("%w: %s: %w", pokeapi.ErrDecode, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+// internal/pokeapi/prefetch.go
+// for warming the cache up front so a session can run offline
+package pokeapi // our internal package pokeapi
+
+import (
+	// standard Go libraries
+	"context" // for cancelling a prefetch
+	"fmt"     // for Errorf printing
+	"sort"    // for stable failure lists
+	"strconv" // for pokemon ids
+	"strings" // for parsing ids out of urls
+	"sync"    // for the worker pool
+)
+
+// DefaultPrefetchWorkers is how many requests a prefetch runs at once
+// the client's rate limiter still applies, this just keeps the limiter busy
+const DefaultPrefetchWorkers = 8
+
+// PrefetchResult reports what a prefetch managed to cache
+type PrefetchResult struct {
+	Fetched int      // resources fetched (and so cached)
+	Failed  []string // names that couldn't be fetched, sorted
+}
+
+// PrefetchProgress is called after every resource with how many are done out of total
+// called from worker goroutines, but never concurrently
+type PrefetchProgress func(done, total int)
+
+// PrefetchLocationAreas walks every location-area list page (the same urls map/mapb use)
+// then fetches every area's details with a pool of workers
+// api is normally a *Client so everything lands in its cache (and persistent store)
+func PrefetchLocationAreas(ctx context.Context, api API, workers int, progress PrefetchProgress) (PrefetchResult, error) {
+	// nil api check
+	if api == nil {
+		return PrefetchResult{}, fmt.Errorf("PrefetchLocationAreas called with nil api") // early return
+	}
+
+	var names []string // every area name, in list order
+
+	// walk the list pages, one after the other (each page has the next url)
+	pageURL := "" // first page
+	for {
+		page, err := api.GetLocationAreasContext(ctx, pageURL)
+
+		// page check, can't know what's left without it
+		if err != nil {
+			return PrefetchResult{}, fmt.Errorf("error listing location areas: %w", err)
+		}
+
+		// mapb follows previous links, which are different urls for the same pages
+		if page.Previous != nil {
+			if _, err := api.GetLocationAreasContext(ctx, *page.Previous); err != nil {
+				return PrefetchResult{}, fmt.Errorf("error listing location areas: %w", err)
+			}
+		}
+
+		for _, area := range page.Results {
+			names = append(names, area.Name)
+		}
+
+		// last page check
+		if page.Next == nil {
+			break
+		}
+		pageURL = *page.Next
+	}
+
+	// fetch the details concurrently
+	return prefetchAll(ctx, names, workers, progress, func(ctx context.Context, name string) error {
+		_, err := api.GetLocationAreaContext(ctx, name)
+		return err
+	})
+}
+
+// PrefetchPokemon fetches every pokemon with an id in [from, to] with a pool of workers
+// pokemon are fetched by NAME (catch looks them up by name, and the cache is keyed on the url)
+// along with their species (capture rates), the pokemon list used for name suggestions gets cached too
+func PrefetchPokemon(ctx context.Context, api API, from, to, workers int, progress PrefetchProgress) (PrefetchResult, error) {
+	// nil api check
+	if api == nil {
+		return PrefetchResult{}, fmt.Errorf("PrefetchPokemon called with nil api") // early return
+	}
+
+	// range check
+	if from < 1 || to < from {
+		return PrefetchResult{}, fmt.Errorf("invalid pokemon id range %d-%d", from, to)
+	}
+
+	// map ids to names
+	list, err := api.GetPokemonListContext(ctx)
+
+	// list check
+	if err != nil {
+		return PrefetchResult{}, fmt.Errorf("error listing pokemon: %w", err)
+	}
+
+	// pick out the ids in range
+	var names []string
+	for _, pokemon := range list.Results {
+		id, ok := resourceID(pokemon.URL)
+		if ok && id >= from && id <= to {
+			names = append(names, pokemon.Name)
+		}
+	}
+
+	// fetch them concurrently
+	return prefetchAll(ctx, names, workers, progress, func(ctx context.Context, name string) error {
+		pokemon, err := api.GetPokemonStatsContext(ctx, name)
+		if err != nil {
+			return err
+		}
+		_, err = api.GetPokemonSpeciesContext(ctx, pokemon.SpeciesName())
+		return err
+	})
+}
+
+// prefetchAll runs fetch for every name with at most workers running at once
+// failures are collected (a prefetch should get as much as it can), cancelling stops it early
+func prefetchAll(ctx context.Context, names []string, workers int, progress PrefetchProgress, fetch func(context.Context, string) error) (PrefetchResult, error) {
+	// workers check
+	if workers < 1 {
+		workers = DefaultPrefetchWorkers
+	}
+
+	// feed names to the workers
+	jobs := make(chan string)
+	go func() {
+		defer close(jobs)
+		for _, name := range names {
+			select {
+			case jobs <- name:
+			case <-ctx.Done():
+				return // stop handing out work
+			}
+		}
+	}()
+
+	var (
+		mu     sync.Mutex     // guards result and done, and serialises progress calls
+		result PrefetchResult // what we got
+		done   int            // finished (either way), for progress
+		wg     sync.WaitGroup // wait for every worker
+	)
+
+	// start the workers
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				err := fetch(ctx, name)
+
+				// lock mutex before updating the result
+				mu.Lock()
+				if err != nil {
+					result.Failed = append(result.Failed, name)
+				} else {
+					result.Fetched++
+				}
+				done++
+				if progress != nil {
+					progress(done, len(names))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// cancelled check, what was fetched is still cached
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	// sorted so output is stable
+	sort.Strings(result.Failed)
+	return result, nil
+}
+
+// resourceID parses the id out of a resource url like ".../pokemon/25/"
+func resourceID(url string) (int, bool) {
+	trimmed := strings.TrimSuffix(url, "/")
+	id, err := strconv.Atoi(trimmed[strings.LastIndex(trimmed, "/")+1:])
+	return id, err == nil
+}
@@ -0,0 +1,71 @@
+// prefetch_test.go
+package pokeapi
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PietPadda/pokedexcli/internal/mockapi"
+	"github.com/PietPadda/pokedexcli/internal/pokecache"
+)
+
+func TestPrefetchThenOffline(t *testing.T) {
+	handler, err := mockapi.NewHandler()
+	if err != nil {
+		t.Fatalf("NewHandler unsuccesful: %v", err)
+	}
+	server := httptest.NewServer(handler)
+
+	cache := pokecache.NewCache(time.Minute)
+	defer cache.Close()
+	client := NewClient(cache, WithBaseURL(server.URL+"/api/v2"), WithMaxAttempts(1), WithRateLimit(0, 0))
+
+	// locations, with progress reported for every area
+	var calls, lastDone, lastTotal int
+	areas, err := PrefetchLocationAreas(context.Background(), &client, 4, func(done, total int) {
+		calls++
+		lastDone, lastTotal = done, total
+	})
+	if err != nil {
+		t.Fatalf("PrefetchLocationAreas unsuccesful: %v", err)
+	}
+	if areas.Fetched == 0 || len(areas.Failed) != 0 {
+		t.Fatalf("expected every area fetched, got %d (failed %v)", areas.Fetched, areas.Failed)
+	}
+	if calls != areas.Fetched || lastDone != lastTotal || lastTotal != areas.Fetched {
+		t.Errorf("expected progress for all %d areas, got %d calls ending %d/%d", areas.Fetched, calls, lastDone, lastTotal)
+	}
+
+	// pokemon by id range, only those in range
+	pokemon, err := PrefetchPokemon(context.Background(), &client, 74, 75, 4, nil)
+	if err != nil {
+		t.Fatalf("PrefetchPokemon unsuccesful: %v", err)
+	}
+	if pokemon.Fetched != 2 {
+		t.Errorf("expected geodude and graveler, got %d (failed %v)", pokemon.Fetched, pokemon.Failed)
+	}
+
+	// server gone, everything prefetched is served from the cache
+	server.Close()
+	page, err := client.GetLocationAreas("")
+	if err != nil {
+		t.Fatalf("expected first page from cache: %v", err)
+	}
+	if _, err := client.GetLocationArea(page.Results[0].Name); err != nil {
+		t.Errorf("expected area from cache: %v", err)
+	}
+	if _, err := client.GetPokemonStats("geodude"); err != nil {
+		t.Errorf("expected geodude from cache: %v", err)
+	}
+	if _, err := client.GetPokemonStats("pikachu"); err == nil {
+		t.Errorf("expected pikachu (out of range) not to be cached")
+	}
+}
+
+func TestPrefetchPokemonRange(t *testing.T) {
+	if _, err := PrefetchPokemon(context.Background(), &Client{}, 10, 5, 1, nil); err == nil {
+		t.Errorf("expected error for backwards range")
+	}
+}
@@ -0,0 +1,99 @@
+// internal/pokeapi/ratelimit.go
+// for being polite to PokeAPI (client-side token bucket)
+package pokeapi // our internal package pokeapi
+
+import (
+	// standard Go libraries
+	"context" // for cancelling waits
+	"fmt"     // for Errorf printing
+	"sync"    // for Mutex on bucket state
+	"time"    // for refill timing
+)
+
+// rate limit defaults, override with WithRateLimit
+const (
+	DefaultRateLimit = 10.0 // requests per second
+	DefaultRateBurst = 10   // requests allowed back to back
+)
+
+// token bucket rate limiter
+// lowercase (private) as its internal use only, shared by ptr so Client copies share ONE bucket
+type rateLimiter struct {
+	mu     *sync.Mutex // protects the fields below
+	rate   float64     // tokens added per second
+	burst  float64     // bucket size
+	tokens float64     // tokens currently available
+	last   time.Time   // last refill time
+}
+
+// constructor function for making a rate limiter
+// rate <= 0 means no limiting (returns nil, which wait treats as unlimited)
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	// disabled check
+	if rate <= 0 {
+		return nil
+	}
+
+	// at least one token or nothing could ever go through
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		mu:     &sync.Mutex{},  // inits the mutex (safe, avoid nil ptr deref)
+		rate:   rate,           // refill speed
+		burst:  float64(burst), // bucket size
+		tokens: float64(burst), // start full so the first requests are instant
+		last:   time.Now(),     // refill from now
+	}
+}
+
+// WithRateLimit sets requests per second and burst size (rate <= 0 disables limiting)
+func WithRateLimit(rate float64, burst int) Option {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(rate, burst)
+	}
+}
+
+// wait blocks until a token is available or ctx is done
+// nil limiter = unlimited
+func (l *rateLimiter) wait(ctx context.Context) error {
+	// unlimited check
+	if l == nil {
+		return nil
+	}
+
+	// loop until we get a token (another goroutine may beat us to it after sleeping)
+	for {
+		// lock mutex before touching the bucket
+		l.mu.Lock()
+
+		// refill based on time passed since last refill
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst // bucket can't overflow
+		}
+		l.last = now
+
+		// token available check
+		if l.tokens >= 1 {
+			l.tokens-- // take one
+			l.mu.Unlock()
+			return nil
+		}
+
+		// how long until the next token drips in
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		// sleep, but wake up if the caller cancels
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("error waiting for rate limiter: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// ratelimit_test.go
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(100, 2) // 10ms per token after the burst
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatalf("wait unsuccesful: %v", err)
+		}
+	}
+
+	// burst of 2 is free, the next 2 need ~10ms each
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("expected limiter to slow down after burst, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	limiter := newRateLimiter(0.001, 1) // one token, then a very long wait
+	limiter.wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRateLimiterSkipsCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	// one request allowed, then nothing for a long time
+	client := NewClient(newTestCache(t), WithBaseURL(server.URL), WithRateLimit(0.001, 1))
+
+	if _, err := client.GetPokemonStats("pikachu"); err != nil {
+		t.Fatalf("GetPokemonStats unsuccesful: %v", err)
+	}
+
+	// cache hit must not wait for a token
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetPokemonStatsContext(ctx, "pikachu"); err != nil {
+		t.Errorf("expected cache hit to bypass limiter, got %v", err)
+	}
+}
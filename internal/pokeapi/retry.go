@@ -0,0 +1,154 @@
+// internal/pokeapi/retry.go
+// for retrying transient PokeAPI failures with backoff
+package pokeapi // our internal package pokeapi
+
+import (
+	// standard Go libraries
+	"context"   // for cancelling waits
+	"fmt"       // for Errorf printing
+	"math/rand" // for backoff jitter
+	"net/http"  // for parsing Retry-After dates
+	"strconv"   // for parsing Retry-After seconds
+	"time"      // for backoff durations
+
+	// internal packages
+	"github.com/PietPadda/pokedexcli/internal/pokecache" // for Validators
+)
+
+// retry defaults, override with WithMaxAttempts / WithBackoff
+const (
+	DefaultMaxAttempts = 3                      // 1 try + 2 retries
+	defaultBackoffBase = 250 * time.Millisecond // first retry waits around this long
+	defaultBackoffMax  = 5 * time.Second        // backoff never grows past this
+	maxRetryAfter      = 30 * time.Second       // longest Retry-After we'll wait, longer hints give up instead
+)
+
+// WithMaxAttempts sets how many times a request is tried before giving up (minimum 1)
+func WithMaxAttempts(attempts int) Option {
+	return func(c *Client) {
+		// at least one try
+		if attempts < 1 {
+			attempts = 1
+		}
+		c.maxAttempts = attempts
+	}
+}
+
+// WithBackoff sets the first retry delay and the cap the exponential backoff grows to
+func WithBackoff(base, max time.Duration) Option {
+	return func(c *Client) {
+		c.backoffBase = base
+		c.backoffMax = max
+	}
+}
+
+// get does an HTTP GET for url, retrying transient failures (connection errors, 429, 5xx)
+// with jittered exponential backoff, honouring the server's Retry-After
+// non-zero validators make it a conditional request (res.notModified on a 304)
+func (c *Client) get(ctx context.Context, url string, validators pokecache.Validators) (response, error) {
+	// attempts check (zero value Client)
+	attempts := c.maxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error // keep the last failure for the final error
+
+	// try up to maxAttempts times
+	for attempt := 0; attempt < attempts; attempt++ {
+		// every attempt (retries too) goes thru the rate limiter, cache hits never get here
+		if err := c.limiter.wait(ctx); err != nil {
+			return response{}, err
+		}
+
+		res, retry, retryAfter, err := c.getOnce(ctx, url, validators)
+
+		// success check
+		if err == nil {
+			return res, nil
+		}
+		lastErr = err
+
+		// fail fast check: caller gave up, or error isn't transient
+		if ctx.Err() != nil || !retry {
+			return response{}, err
+		}
+
+		// last attempt, don't bother waiting
+		if attempt == attempts-1 {
+			break
+		}
+
+		// wait before retrying, server hint wins over our own backoff
+		wait := c.backoff(attempt)
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+
+		// too long check, retrying any sooner than the server allows would just get another 429
+		// so give up with its error if we won't (or the caller can't) wait that long
+		deadline, hasDeadline := ctx.Deadline()
+		if retryAfter > maxRetryAfter || (hasDeadline && time.Now().Add(wait).After(deadline)) {
+			return response{}, fmt.Errorf("not retrying, would have to wait %v: %w", wait, err)
+		}
+
+		// sleep, but wake up if the caller cancels
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response{}, fmt.Errorf("error waiting to retry: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	// every attempt failed
+	return response{}, fmt.Errorf("giving up after %d attempts: %w", attempts, lastErr)
+}
+
+// backoff returns the jittered delay before retry number attempt (0 = first retry)
+// doubles each time up to backoffMax, jitter keeps many clients from retrying in lockstep
+func (c *Client) backoff(attempt int) time.Duration {
+	// exponential: base * 2^attempt, capped
+	delay := c.backoffBase
+	for i := 0; i < attempt && delay < c.backoffMax; i++ {
+		delay *= 2
+	}
+	if delay > c.backoffMax {
+		delay = c.backoffMax
+	}
+
+	// no delay check (tests)
+	if delay <= 0 {
+		return 0
+	}
+
+	// equal jitter: half fixed, half random
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter reads a Retry-After header (seconds or HTTP date), 0 if missing or invalid
+// never shortened, get gives up on hints it won't wait for
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	// empty check
+	if header == "" {
+		return 0
+	}
+
+	var wait time.Duration
+
+	// seconds form: "Retry-After: 120"
+	if seconds, err := strconv.Atoi(header); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(header); err == nil {
+		// date form: "Retry-After: Wed, 21 Oct 2015 07:28:00 GMT"
+		wait = date.Sub(now)
+	}
+
+	// already passed check
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
@@ -0,0 +1,119 @@
+// retry_test.go
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	cases := []struct {
+		name         string
+		statuses     []int // status per request, 200 after the list runs out
+		expectErr    bool
+		expectedHits int
+	}{
+		{name: "transient 503", statuses: []int{503, 503}, expectErr: false, expectedHits: 3},
+		{name: "rate limited", statuses: []int{429}, expectErr: false, expectedHits: 2},
+		{name: "not found fails fast", statuses: []int{404, 404, 404}, expectErr: true, expectedHits: 1},
+		{name: "gives up", statuses: []int{500, 500, 500, 500}, expectErr: true, expectedHits: 3},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hits := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits++
+				if hits <= len(c.statuses) {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(c.statuses[hits-1])
+					return
+				}
+				w.Write([]byte(`{"name": "pikachu"}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(newTestCache(t),
+				WithBaseURL(server.URL),
+				WithMaxAttempts(3),
+				WithBackoff(time.Millisecond, 2*time.Millisecond),
+			)
+
+			_, err := client.GetPokemonStats("pikachu")
+			if (err != nil) != c.expectErr {
+				t.Errorf("expected error: %v, got %v", c.expectErr, err)
+			}
+			if hits != c.expectedHits {
+				t.Errorf("expected %d requests, got %d", c.expectedHits, hits)
+			}
+		})
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	cases := []struct {
+		name       string
+		retryAfter string
+		timeout    time.Duration // 0 = no deadline
+	}{
+		{name: "past the cap", retryAfter: "3600"},
+		{name: "past the deadline", retryAfter: "5", timeout: time.Second},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hits := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits++
+				w.Header().Set("Retry-After", c.retryAfter)
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer server.Close()
+
+			client := NewClient(newTestCache(t), WithBaseURL(server.URL), WithMaxAttempts(3))
+
+			ctx := context.Background()
+			if c.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, c.timeout)
+				defer cancel()
+			}
+
+			// gives up straight away with the 429, never retries early
+			start := time.Now()
+			_, err := client.GetPokemonStatsContext(ctx, "pikachu")
+			if !errors.Is(err, ErrRateLimited) {
+				t.Errorf("expected ErrRateLimited, got %v", err)
+			}
+			if hits != 1 || time.Since(start) > 500*time.Millisecond {
+				t.Errorf("expected 1 request and no wait, got %d requests in %v", hits, time.Since(start))
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC)
+	cases := []struct {
+		header   string
+		expected time.Duration
+	}{
+		{header: "", expected: 0},
+		{header: "2", expected: 2 * time.Second},
+		{header: "nonsense", expected: 0},
+		{header: "Wed, 21 Oct 2015 07:28:10 GMT", expected: 10 * time.Second},
+		{header: "Wed, 21 Oct 2015 07:27:00 GMT", expected: 0},
+		{header: "86400", expected: 24 * time.Hour},
+	}
+
+	for _, c := range cases {
+		actual := parseRetryAfter(c.header, now)
+		if actual != c.expected {
+			t.Errorf("parseRetryAfter(%q): expected %v, got %v", c.header, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+// internal/pokeapi/savefile.go
+// for persisting the Pokedex to disk across sessions
+package pokeapi // our internal package pokeapi
+
+import (
+	// standard Go libraries
+	"bytes"         // for compacting the checksummed payload
+	"crypto/sha256" // for save file checksum (detects corrupt saves)
+	"encoding/hex"  // for storing checksum as readable string
+	"encoding/json" // for marshalling the save file
+	"errors"        // for sentinel errors
+	"fmt"           // for Errorf printing
+	"os"            // for file reading/writing
+	"path/filepath" // for building the save path
+	"sort"          // for stable seen lists and migrated box ids
+)
+
+// current save file format version, bump when saveData changes shape
+// older versions are migrated on load (see decodeSaveFile)
+// 1: pokemon only
+// 2: + inventory items
+// 3: + species seen, box of individual caught pokemon
+const saveFileVersion = 3
+
+// ErrCorruptSave is returned when a save file is partial, tampered with or unreadable
+// capped (public) so callers can check it with errors.Is
+var ErrCorruptSave = errors.New("corrupt save file")
+
+// SAVE FILE STRUCTS
+// on-disk save file wrapper (SF) -- versioned and checksummed
+type saveFile struct {
+	Version  int             `json:"version"`  // save file format version
+	Checksum string          `json:"checksum"` // sha256 of the compact data payload
+	Data     json.RawMessage `json:"data"`     // raw payload, checksummed as-is
+}
+
+// save file payload (SD) -- what actually gets persisted
+type saveData struct {
+	Pokemon map[string]PokemonStats `json:"pokemon"` // pokedex entries
+	Items   map[string]int          `json:"items"`   // inventory, item name -> count (v2+)
+	Seen    []string                `json:"seen"`    // species seen, sorted (v3+)
+	Box     []CaughtPokemon         `json:"box"`     // caught individuals, in id order (v3+)
+	NextID  int                     `json:"next_id"` // last box id handed out (v3+)
+}
+
+// migratedLevel is the level pokemon caught before v3 saves get, levels weren't kept back then
+const migratedLevel = 5
+
+// DefaultSavePath returns the default save file location
+// uses $XDG_DATA_HOME, falling back to ~/.local/share as per the XDG spec
+func DefaultSavePath() (string, error) {
+	// get XDG data dir
+	dataDir := os.Getenv("XDG_DATA_HOME")
+
+	// not set check, fallback to spec default
+	if dataDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error finding home directory: %w", err) // early return
+		}
+		dataDir = filepath.Join(home, ".local", "share") // XDG default
+	}
+
+	// return full save path
+	return filepath.Join(dataDir, "pokedexcli", "pokedex.json"), nil
+}
+
+// pokedex set save path function -- enables autosave after every PokemonAdd
+// takes a file path, empty path disables autosave
+func (p *Pokedex) SetSavePath(path string) error {
+	// nil ptr check
+	if p == nil {
+		return fmt.Errorf("SetSavePath called with nil receiver") // early return
+	}
+
+	// lock mutex before updating path
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.savePath = path // store for autosave and default save/load
+	return nil
+}
+
+// pokedex save path function -- returns the configured save path (empty if none)
+func (p *Pokedex) SavePath() string {
+	// nil ptr check
+	if p == nil {
+		return "" // nothing configured
+	}
+
+	// READ lock mutex before reading path
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.savePath
+}
+
+// pokedex save function -- atomically writes the pokedex to disk
+// takes a file path, empty path uses the configured save path
+func (p *Pokedex) Save(path string) error {
+	// nil ptr check
+	if p == nil {
+		return fmt.Errorf("Save called with nil receiver") // early return
+	}
+
+	// READ lock mutex, we only read the map while saving
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	// use configured path if none given
+	if path == "" {
+		path = p.savePath
+	}
+
+	return p.saveLocked(path) // save while still holding the lock
+}
+
+// pokedex load function -- replaces the pokedex with the contents of a save file
+// takes a file path, empty path uses the configured save path
+// returns an error wrapping fs.ErrNotExist if there is no save yet, or ErrCorruptSave
+func (p *Pokedex) Load(path string) error {
+	// nil ptr check
+	if p == nil {
+		return fmt.Errorf("Load called with nil receiver") // early return
+	}
+
+	// use configured path if none given
+	if path == "" {
+		path = p.SavePath()
+	}
+
+	// path check
+	if path == "" {
+		return fmt.Errorf("no save path configured") // early return
+	}
+
+	// read the whole save file
+	raw, err := os.ReadFile(path)
+
+	// read file check
+	if err != nil {
+		return fmt.Errorf("error reading save file: %w", err) // keeps fs.ErrNotExist for callers
+	}
+
+	// decode and verify the save file
+	data, err := decodeSaveFile(raw)
+
+	// decode check
+	if err != nil {
+		return fmt.Errorf("error loading %s: %w", path, err)
+	}
+
+	// lock mutex before replacing map
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// replace pokedex contents with the loaded entries
+	p.pokemon = data.Pokemon
+	p.items = data.Items
+	p.seen = make(map[string]bool, len(data.Seen))
+	for _, name := range data.Seen {
+		p.seen[name] = true
+	}
+	for name := range data.Pokemon {
+		p.seen[name] = true // caught means seen, even if the save forgot
+	}
+	p.box = make(map[int]CaughtPokemon, len(data.Box))
+	for _, caught := range data.Box {
+		p.box[caught.ID] = caught
+	}
+	p.nextID = data.NextID
+
+	// successfully loaded save
+	return nil
+}
+
+// autosaveLocked saves to the configured path if there is one, caller MUST hold p.mu (write)
+func (p *Pokedex) autosaveLocked() error {
+	// autosave off check
+	if p.savePath == "" {
+		return nil
+	}
+
+	// autosave check (the change is still in memory)
+	if err := p.saveLocked(p.savePath); err != nil {
+		return fmt.Errorf("autosave failed: %w", err)
+	}
+	return nil
+}
+
+// saveLocked writes the pokedex to path, caller MUST hold p.mu (read or write)
+// writes to a temp file then renames so a crash never leaves a half written save
+func (p *Pokedex) saveLocked(path string) error {
+	// path check
+	if path == "" {
+		return fmt.Errorf("no save path configured") // early return
+	}
+
+	// seen as a sorted list, so saves don't churn
+	seen := make([]string, 0, len(p.seen))
+	for name := range p.seen {
+		seen = append(seen, name)
+	}
+	sort.Strings(seen)
+
+	// encode the save file
+	raw, err := encodeSaveFile(saveData{
+		Pokemon: p.pokemon,
+		Items:   p.items,
+		Seen:    seen,
+		Box:     p.boxLocked(),
+		NextID:  p.nextID,
+	})
+
+	// encode check
+	if err != nil {
+		return err
+	}
+
+	// make sure the save dir exists
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("error creating save directory: %w", err)
+	}
+
+	// create temp file in SAME dir (rename is only atomic within a filesystem)
+	tmp, err := os.CreateTemp(dir, ".pokedex-*.tmp")
+
+	// create temp check
+	if err != nil {
+		return fmt.Errorf("error creating temp save file: %w", err)
+	}
+	tmpName := tmp.Name()    // keep name for rename/cleanup
+	defer os.Remove(tmpName) // no-op after successful rename
+
+	// write the save data
+	_, err = tmp.Write(raw)
+	if err == nil {
+		err = tmp.Sync() // flush to disk before rename
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr // report close error if nothing else failed
+	}
+
+	// write check
+	if err != nil {
+		return fmt.Errorf("error writing save file: %w", err)
+	}
+
+	// atomically swap in the new save
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("error replacing save file: %w", err)
+	}
+
+	// successfully saved
+	return nil
+}
+
+// encodeSaveFile wraps the payload in a versioned, checksummed save file
+func encodeSaveFile(data saveData) ([]byte, error) {
+	// marshal the payload compactly, this is what gets checksummed
+	payload, err := json.Marshal(data)
+
+	// marshal check
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling save data: %w", err)
+	}
+
+	// checksum the payload
+	sum := sha256.Sum256(payload)
+
+	// wrap it up with version and checksum
+	file := saveFile{
+		Version:  saveFileVersion,
+		Checksum: hex.EncodeToString(sum[:]),
+		Data:     payload,
+	}
+
+	// indent for human readable saves
+	raw, err := json.MarshalIndent(file, "", "  ")
+
+	// marshal check
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling save file: %w", err)
+	}
+	return raw, nil
+}
+
+// decodeSaveFile unwraps and verifies a save file, any problem is reported as ErrCorruptSave
+func decodeSaveFile(raw []byte) (saveData, error) {
+	// unmarshal the wrapper (a truncated file fails here)
+	var file saveFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return saveData{}, fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+
+	// version check (older versions are migrated below, newer ones we can't know about)
+	if file.Version < 1 || file.Version > saveFileVersion {
+		return saveData{}, fmt.Errorf("%w: unsupported version %d", ErrCorruptSave, file.Version)
+	}
+
+	// missing payload check
+	if len(file.Data) == 0 {
+		return saveData{}, fmt.Errorf("%w: missing data", ErrCorruptSave)
+	}
+
+	// compact payload back to how it was checksummed (MarshalIndent re-indents raw messages)
+	var payload bytes.Buffer
+	if err := json.Compact(&payload, file.Data); err != nil {
+		return saveData{}, fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+
+	// checksum check
+	sum := sha256.Sum256(payload.Bytes())
+	if hex.EncodeToString(sum[:]) != file.Checksum {
+		return saveData{}, fmt.Errorf("%w: checksum mismatch", ErrCorruptSave)
+	}
+
+	// unmarshal the verified payload
+	var data saveData
+	if err := json.Unmarshal(payload.Bytes(), &data); err != nil {
+		return saveData{}, fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+
+	// nil map check, keep pokedex usable
+	if data.Pokemon == nil {
+		data.Pokemon = make(map[string]PokemonStats)
+	}
+
+	// migrate v1 -> v2: no inventory back then, hand out the starter items
+	if file.Version < 2 {
+		data.Items = StarterItems()
+	}
+
+	// nil map check (an empty bag saves as null)
+	if data.Items == nil {
+		data.Items = make(map[string]int)
+	}
+
+	// migrate v2 -> v3: one of each caught species goes in the box, all of them seen
+	if file.Version < 3 {
+		data.Seen, data.Box, data.NextID = migrateBox(data.Pokemon)
+	}
+
+	// id check, never hand out an id that's already boxed (a hand edited save)
+	for _, caught := range data.Box {
+		data.NextID = max(data.NextID, caught.ID)
+	}
+	return data, nil
+}
+
+// migrateBox builds the v3 seen list and box from a v1/v2 save's pokemon
+// only species were kept back then, so each becomes one level 5 pokemon with
+// no ivs, a neutral nature, and unknown caught-at time and location
+func migrateBox(pokemon map[string]PokemonStats) ([]string, []CaughtPokemon, int) {
+	// sorted so ids come out the same every time
+	names := make([]string, 0, len(pokemon))
+	for name := range pokemon {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	box := make([]CaughtPokemon, 0, len(names))
+	for i, name := range names {
+		box = append(box, CaughtPokemon{
+			ID:      i + 1,
+			Species: name,
+			Level:   migratedLevel,
+			Nature:  "hardy", // neutral, no stat changes
+		})
+	}
+	return names, box, len(box)
+}
@@ -0,0 +1,175 @@
+// savefile_test.go
+package pokeapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "pokedex.json")
+
+	pokedex := NewPokedex()
+	pokedex.SetSavePath(path)
+	err := pokedex.PokemonAdd("pikachu", PokemonStats{Name: "pikachu", ID: 25, Height: 4})
+	if err != nil {
+		t.Fatalf("PokemonAdd unsuccesful: %v", err)
+	}
+
+	loaded := NewPokedex()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load unsuccesful: %v", err)
+	}
+	pokemon, ok, err := loaded.PokemonGet("pikachu")
+	if err != nil || !ok {
+		t.Fatalf("expected to find autosaved pokemon")
+	}
+	if pokemon.ID != 25 || pokemon.Height != 4 {
+		t.Errorf("expected loaded stats to match, got %+v", pokemon)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	err := NewPokedex().Load(filepath.Join(t.TempDir(), "missing.json"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	pokedex := NewPokedex()
+	pokedex.PokemonAdd("pikachu", PokemonStats{Name: "pikachu"})
+	if err := pokedex.Save(path); err != nil {
+		t.Fatalf("Save unsuccesful: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string][]byte{
+		"truncated": raw[:len(raw)/2],
+		"tampered":  []byte(strings.ReplaceAll(string(raw), "pikachu", "raichu")),
+		"empty":     {},
+		"version":   []byte(`{"version": 99, "checksum": "", "data": {}}`),
+	}
+
+	for name, contents := range cases {
+		t.Run(name, func(t *testing.T) {
+			os.WriteFile(path, contents, 0o644)
+			err := NewPokedex().Load(path)
+			if !errors.Is(err, ErrCorruptSave) {
+				t.Errorf("expected ErrCorruptSave, got %v", err)
+			}
+		})
+	}
+}
+
+func TestInventorySaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+
+	pokedex := NewPokedex()
+	pokedex.SetSavePath(path)
+	inventory := pokedex.Inventory()
+	if err := inventory.Use(ItemMasterBall); err != nil {
+		t.Fatalf("Use unsuccesful: %v", err)
+	}
+	if err := inventory.Use(ItemMasterBall); !errors.Is(err, ErrNoItem) {
+		t.Errorf("expected ErrNoItem, got %v", err)
+	}
+	inventory.Add(ItemGreatBall, 2)
+
+	// autosaved, so a fresh pokedex sees the same bag
+	loaded := NewPokedex()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load unsuccesful: %v", err)
+	}
+	items := loaded.Inventory().Items()
+	if items[ItemMasterBall] != 0 || items[ItemGreatBall] != StarterItems()[ItemGreatBall]+2 {
+		t.Errorf("expected loaded bag to match, got %v", items)
+	}
+}
+
+func TestLoadVersion1(t *testing.T) {
+	// a v1 save, from before inventories
+	payload := `{"pokemon":{"pikachu":{"name":"pikachu","id":25}}}`
+	sum := sha256.Sum256([]byte(payload))
+	raw := `{"version": 1, "checksum": "` + hex.EncodeToString(sum[:]) + `", "data": ` + payload + `}`
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	if err := os.WriteFile(path, []byte(raw), 0o644); err != nil {
+		t.Fatalf("WriteFile unsuccesful: %v", err)
+	}
+
+	pokedex := NewPokedex()
+	if err := pokedex.Load(path); err != nil {
+		t.Fatalf("Load unsuccesful: %v", err)
+	}
+	if _, ok, _ := pokedex.PokemonGet("pikachu"); !ok {
+		t.Errorf("expected v1 pokemon to load")
+	}
+	if count := pokedex.Inventory().Count(ItemPokeBall); count != StarterItems()[ItemPokeBall] {
+		t.Errorf("expected starter pokeballs after migrating, got %d", count)
+	}
+	box, _ := pokedex.Box()
+	if len(box) != 1 || box[0].ID != 1 || box[0].Species != "pikachu" || box[0].Level != migratedLevel {
+		t.Errorf("expected v1 pikachu boxed as #1 at level %d, got %+v", migratedLevel, box)
+	}
+}
+
+func TestBoxSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	pokedex := NewPokedex()
+	pokedex.SetSavePath(path) // autosave
+
+	// two of the same species are two different pokemon
+	first, err := pokedex.Catch(PokemonStats{Name: "pikachu"}, CaughtPokemon{Species: "pikachu", Level: 5, Nature: "brave", IVs: IVs{HP: 31}})
+	if err != nil {
+		t.Fatalf("Catch unsuccesful: %v", err)
+	}
+	second, _ := pokedex.Catch(PokemonStats{Name: "pikachu"}, CaughtPokemon{Species: "pikachu", Level: 9, Location: "eterna-forest-area"})
+	if first.ID != 1 || second.ID != 2 || second.CaughtAt.IsZero() {
+		t.Fatalf("expected ids 1 and 2 with a caught-at time, got %+v and %+v", first, second)
+	}
+	pokedex.PokemonSeen("gyarados")
+
+	// nickname one, release the other
+	if _, err := pokedex.Nickname(first.ID, "sparky"); err != nil {
+		t.Fatalf("Nickname unsuccesful: %v", err)
+	}
+	if _, err := pokedex.Release(second.ID); err != nil {
+		t.Fatalf("Release unsuccesful: %v", err)
+	}
+	if _, err := pokedex.Release(second.ID); !errors.Is(err, ErrNotInBox) {
+		t.Errorf("expected ErrNotInBox releasing twice, got %v", err)
+	}
+
+	// load the autosave into a fresh pokedex
+	loaded := NewPokedex()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load unsuccesful: %v", err)
+	}
+	box, _ := loaded.Box()
+	if len(box) != 1 || box[0].DisplayName() != "sparky" || box[0].IVs.HP != 31 || box[0].Nature != "brave" {
+		t.Errorf("expected sparky the brave pikachu in the box, got %+v", box)
+	}
+	seen, _ := loaded.PokemonGetAllSeen()
+	if len(seen) != 2 {
+		t.Errorf("expected pikachu and gyarados seen, got %v", seen)
+	}
+	if _, ok, _ := loaded.PokemonGet("pikachu"); !ok {
+		t.Errorf("expected pikachu to stay caught in the Pokedex after a release")
+	}
+
+	// ids are never reused, even after a release and a reload
+	third, _ := loaded.Catch(PokemonStats{Name: "magikarp"}, CaughtPokemon{Species: "magikarp"})
+	if third.ID != 3 {
+		t.Errorf("expected the next id to be 3, got %d", third.ID)
+	}
+}
@@ -0,0 +1,87 @@
+// internal/pokeapi/singleflight.go
+// for sharing one request between concurrent callers asking for the same url
+package pokeapi // our internal package pokeapi
+
+import (
+	// standard Go libraries
+	"context" // for letting waiters give up
+	"fmt"     // for Errorf printing
+	"sync"    // for Mutex on in-flight map
+	"time"    // for the detached request's deadline
+)
+
+// in-flight request (IF) -- waiters block on done, then read val/err
+// lowercase (private) as its internal use only
+type flightCall struct {
+	done chan struct{} // closed when the request finishes
+	val  any           // decoded response, shared by every waiter (read-only once done is closed)
+	err  error         // request error
+}
+
+// group of in-flight requests keyed on full url
+// shared by ptr so Client copies share ONE group
+type flightGroup struct {
+	mu    *sync.Mutex            // protects calls map
+	calls map[string]*flightCall // in-flight requests
+}
+
+// constructor function for making a flight group
+func newFlightGroup() *flightGroup {
+	return &flightGroup{
+		mu:    &sync.Mutex{},                // inits the mutex (safe, avoid nil ptr deref)
+		calls: make(map[string]*flightCall), // inits the in-flight map
+	}
+}
+
+// do runs fn for key, unless a call for key is already running, then it waits for that one instead
+// fn runs detached from the callers (see flightContext), so one caller giving up (Ctrl+C) doesn't
+// fail the others, each caller's own ctx only decides how long THEY wait
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (any, error)) (any, error) {
+	// no group check (zero value Client), just run it
+	if g == nil {
+		return fn(ctx)
+	}
+
+	// lock mutex before accessing map
+	g.mu.Lock()
+
+	// not in flight check, first caller registers the call and starts it
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+
+		work, cancel := flightContext(ctx)
+		go func() {
+			defer cancel() // release the deadline timer
+
+			// do the work, then wake up the waiters
+			call.val, call.err = fn(work)
+
+			// remove BEFORE closing so new callers after this point start a fresh request (or hit the cache)
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	// wait for it, or until this caller gives up
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("error waiting for in-flight request: %w", ctx.Err())
+	}
+}
+
+// flightContext is what a shared request runs under: ctx's values and deadline, but NOT its cancel
+// no deadline means flightTimeout, so a request nobody waits for anymore still ends
+func flightContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(flightTimeout)
+	}
+	return context.WithDeadline(context.WithoutCancel(ctx), deadline)
+}
@@ -0,0 +1,138 @@
+// singleflight_test.go
+package pokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConcurrentFetchCoalesced(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(20 * time.Millisecond) // keep the request in flight while the others arrive
+		w.Write([]byte(`{"name": "pikachu", "id": 25}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestCache(t), WithBaseURL(server.URL))
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pokemon, err := client.GetPokemonStats("pikachu")
+			if err == nil && pokemon.ID != 25 {
+				t.Errorf("expected id 25, got %d", pokemon.ID)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetPokemonStats unsuccesful: %v", err)
+		}
+	}
+	if hits.Load() != 1 {
+		t.Errorf("expected 1 request, got %d", hits.Load())
+	}
+}
+
+func TestConcurrentFetchFirstCallerCancels(t *testing.T) {
+	arrived := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(arrived)
+		<-release // keep the request in flight until the first caller has given up
+		w.Write([]byte(`{"name": "pikachu", "id": 25}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestCache(t), WithBaseURL(server.URL))
+
+	// first caller starts the request, then presses Ctrl+C
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := client.GetPokemonStatsContext(ctx, "pikachu")
+		firstErr <- err
+	}()
+	<-arrived
+
+	// second caller joins the same request
+	second := make(chan error, 1)
+	go func() {
+		pokemon, err := client.GetPokemonStats("pikachu")
+		if err == nil && pokemon.ID != 25 {
+			t.Errorf("expected id 25, got %d", pokemon.ID)
+		}
+		second <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // let the second caller start waiting
+
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected first caller to get context.Canceled, got %v", err)
+	}
+
+	// the shared request carries on for the second caller
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("expected second caller to get the result, got %v", err)
+	}
+}
+
+// countedPokemon counts how many times it's unmarshalled
+type countedPokemon struct {
+	Name string `json:"name"`
+}
+
+var countedDecodes atomic.Int32
+
+func (p *countedPokemon) UnmarshalJSON(raw []byte) error {
+	countedDecodes.Add(1)
+	type plain countedPokemon // no UnmarshalJSON, no recursion
+	return json.Unmarshal(raw, (*plain)(p))
+}
+
+func TestConcurrentFetchDecodedOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond) // keep the request in flight while the others arrive
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestCache(t), WithBaseURL(server.URL))
+
+	// every caller shares the one decode done before caching
+	countedDecodes.Store(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pokemon, err := fetch[countedPokemon](context.Background(), &client, server.URL+"/pokemon/pikachu")
+			if err != nil || pokemon.Name != "pikachu" {
+				t.Errorf("expected pikachu, got %+v (%v)", pokemon, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if decodes := countedDecodes.Load(); decodes != 1 {
+		t.Errorf("expected 1 decode, got %d", decodes)
+	}
+}
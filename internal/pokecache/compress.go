@@ -0,0 +1,79 @@
+// internal/pokecache/compress.go
+// for gzipping large cached values (pokemon bodies are mostly sprites/moves boilerplate)
+package pokecache
+
+import (
+	"bytes"         // for compression buffers
+	"compress/gzip" // stdlib compression, good enough for json
+	"fmt"
+	"io" // for reading the decompressed value
+)
+
+// WithCompression gzips values of at least minSize bytes (0 = off)
+// compression is transparent, CacheGet etc always return the original bytes
+// compressed sizes are what count towards WithMaxBytes, so more entries fit
+func WithCompression(minSize int) Option {
+	return func(c *Cache) {
+		c.compressMin = minSize
+	}
+}
+
+// compress gzips entry.val if it's big enough and actually shrinks
+// only reads compressMin (fixed at construction), so callers don't need the lock
+func (c *Cache) compress(entry cacheEntry) cacheEntry {
+	// off or too small check
+	if c.compressMin <= 0 || len(entry.val) < c.compressMin {
+		return entry
+	}
+
+	// gzip the value
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(entry.val)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr // report close error if nothing else failed
+	}
+
+	// compress check, or it didn't help (already compressed data etc), keep it raw
+	if err != nil || buf.Len() >= len(entry.val) {
+		return entry
+	}
+
+	entry.rawSize = len(entry.val)
+	entry.val = buf.Bytes()
+	entry.compressed = true
+	return entry
+}
+
+// value returns the entry's original bytes, decompressing if needed
+func (entry cacheEntry) value() ([]byte, error) {
+	// raw check
+	if !entry.compressed {
+		return entry.val, nil
+	}
+
+	// gunzip the value
+	zr, err := gzip.NewReader(bytes.NewReader(entry.val))
+
+	// reader check
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing cache entry: %w", err)
+	}
+	defer zr.Close()
+
+	val, err := io.ReadAll(zr)
+
+	// read check
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing cache entry: %w", err)
+	}
+	return val, nil
+}
+
+// rawEntrySize is how many bytes an entry would count towards maxBytes uncompressed
+func rawEntrySize(key string, entry cacheEntry) int {
+	if entry.compressed {
+		return len(key) + entry.rawSize
+	}
+	return entrySize(key, entry)
+}
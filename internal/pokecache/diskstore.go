@@ -0,0 +1,239 @@
+// internal/pokecache/diskstore.go
+// for persisting cache entries to disk between runs
+package pokecache
+
+import (
+	"crypto/sha256" // for hashing keys into safe file names
+	"encoding/hex"  // for hex file names
+	"encoding/json" // for on-disk entry format
+	"errors"        // for missing file check
+	"fmt"
+	"io/fs" // for fs.ErrNotExist
+	"os"    // for file reading/writing
+	"path/filepath"
+	"strings" // for file suffix check
+	"time"    // for createdAt
+)
+
+// on-disk file suffix for cache entries
+const diskEntrySuffix = ".json"
+
+// disk store -- one file per cache key inside dir
+// lowercase (private) as its internal use only
+type diskStore struct {
+	dir string // directory holding the entry files
+}
+
+// on-disk cache entry (DE) -- exported fields for json only
+type diskEntry struct {
+	Key       string        `json:"key"`           // original key (file name is a hash)
+	CreatedAt time.Time     `json:"created_at"`    // when the entry was fetched, for TTL
+	Val       []byte        `json:"val"`           // raw data (base64 in json)
+	TTL       time.Duration `json:"ttl,omitempty"` // per-entry ttl (0 = cache interval)
+
+	ETag         string `json:"etag,omitempty"` // validators for conditional refreshes
+	LastModified string `json:"last_modified,omitempty"`
+
+	Compressed bool `json:"compressed,omitempty"` // Val is gzipped
+	RawSize    int  `json:"raw_size,omitempty"`   // len of the original value when compressed
+}
+
+// constructor function for making a disk store
+// creates the directory if needed
+func newDiskStore(dir string) (*diskStore, error) {
+	// dir check
+	if dir == "" {
+		return nil, fmt.Errorf("cache directory cannot be empty") // early return
+	}
+
+	// make sure dir exists
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating cache directory: %w", err)
+	}
+
+	return &diskStore{dir: dir}, nil
+}
+
+// path returns the entry file for a key (hashed so any url is a safe file name)
+func (d *diskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+diskEntrySuffix)
+}
+
+// put writes an entry to disk atomically (temp file + rename)
+func (d *diskStore) put(key string, entry cacheEntry) error {
+	// marshal the entry
+	raw, err := json.Marshal(diskEntry{
+		Key:          key,
+		CreatedAt:    entry.createdAt,
+		Val:          entry.val,
+		TTL:          entry.ttl,
+		ETag:         entry.validators.ETag,
+		LastModified: entry.validators.LastModified,
+		Compressed:   entry.compressed,
+		RawSize:      entry.rawSize,
+	})
+
+	// marshal check
+	if err != nil {
+		return fmt.Errorf("error marshalling cache entry: %w", err)
+	}
+
+	// write to temp file first so readers never see a partial entry
+	tmp, err := os.CreateTemp(d.dir, ".entry-*.tmp")
+
+	// create temp check
+	if err != nil {
+		return fmt.Errorf("error creating cache file: %w", err)
+	}
+	tmpName := tmp.Name()    // keep name for rename/cleanup
+	defer os.Remove(tmpName) // no-op after successful rename
+
+	// write the entry
+	_, err = tmp.Write(raw)
+	if err == nil {
+		err = tmp.Sync() // flush to disk before rename, so flush's dir sync makes it durable
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr // report close error if nothing else failed
+	}
+
+	// write check
+	if err != nil {
+		return fmt.Errorf("error writing cache file: %w", err)
+	}
+
+	// swap in the new entry
+	if err := os.Rename(tmpName, d.path(key)); err != nil {
+		return fmt.Errorf("error replacing cache file: %w", err)
+	}
+	return nil
+}
+
+// get reads an entry from disk, returns false if missing or unreadable
+// unreadable files are removed, the cache is best effort and can always refetch
+func (d *diskStore) get(key string) (cacheEntry, bool) {
+	path := d.path(key)
+
+	// read the entry file
+	raw, err := os.ReadFile(path)
+
+	// read check
+	if err != nil {
+		return cacheEntry{}, false // missing (or unreadable), treat as miss
+	}
+
+	// unmarshal the entry
+	var entry diskEntry
+	err = json.Unmarshal(raw, &entry)
+
+	// unmarshal + key check (hash collision paranoia)
+	if err != nil || entry.Key != key {
+		os.Remove(path) // corrupt, drop it
+		return cacheEntry{}, false
+	}
+
+	return entry.cacheEntry(), true
+}
+
+// cacheEntry converts back to the in-memory entry
+func (de diskEntry) cacheEntry() cacheEntry {
+	return cacheEntry{
+		createdAt:  de.CreatedAt,
+		val:        de.Val,
+		ttl:        de.TTL,
+		validators: Validators{ETag: de.ETag, LastModified: de.LastModified},
+		compressed: de.Compressed,
+		rawSize:    de.RawSize,
+	}
+}
+
+// remove deletes an entry from disk, missing entries are not an error
+func (d *diskStore) remove(key string) error {
+	err := os.Remove(d.path(key))
+
+	// remove check
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error removing cache file: %w", err)
+	}
+	return nil
+}
+
+// clear deletes every entry file
+func (d *diskStore) clear() error {
+	// list cache dir
+	files, err := os.ReadDir(d.dir)
+
+	// read dir check
+	if err != nil {
+		return fmt.Errorf("error reading cache directory: %w", err)
+	}
+
+	// loop thru entry files and remove them (leave anything that isn't ours)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), diskEntrySuffix) {
+			continue
+		}
+		err := os.Remove(filepath.Join(d.dir, file.Name()))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error removing cache file: %w", err)
+		}
+	}
+	return nil
+}
+
+// flush makes every entry written so far durable
+// put fsyncs each entry file before renaming it, so this only needs to sync the dir (renames live there)
+func (d *diskStore) flush() error {
+	// open the dir to sync it
+	dir, err := os.Open(d.dir)
+
+	// open check
+	if err != nil {
+		return fmt.Errorf("error opening cache directory: %w", err)
+	}
+	defer dir.Close()
+
+	// sync check (some platforms can't sync dirs, nothing more we can do there)
+	if err := dir.Sync(); err != nil && !errors.Is(err, os.ErrInvalid) {
+		return fmt.Errorf("error syncing cache directory: %w", err)
+	}
+	return nil
+}
+
+// reap deletes every entry file the cache says is reapable (past its ttl and any keep windows)
+func (d *diskStore) reap(reapable func(entry cacheEntry, now time.Time) bool) {
+	// list cache dir
+	files, err := os.ReadDir(d.dir)
+
+	// read dir check
+	if err != nil {
+		return // nothing we can do, try again next tick
+	}
+
+	// loop thru entry files and remove expired ones
+	for _, file := range files {
+		// skip temp files and anything that isn't ours
+		if file.IsDir() || !strings.HasSuffix(file.Name(), diskEntrySuffix) {
+			continue
+		}
+
+		path := filepath.Join(d.dir, file.Name())
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			continue // vanished or unreadable, skip
+		}
+
+		// unmarshal to get createdAt
+		var entry diskEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			os.Remove(path) // corrupt
+			continue
+		}
+
+		// expired check
+		if reapable(entry.cacheEntry(), time.Now()) {
+			os.Remove(path)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// internal/pokecache/lru.go
+// for capping cache memory with least-recently-used eviction
+package pokecache
+
+// Option configures a Cache in NewCache / NewPersistentCache
+// capped (public) so main and tests can pass them
+type Option func(*Cache)
+
+// WithMaxEntries caps how many entries are kept in memory (0 = unlimited)
+// least recently used entries are evicted first, a disk store still keeps them
+func WithMaxEntries(n int) Option {
+	return func(c *Cache) {
+		c.maxEntries = n
+	}
+}
+
+// WithMaxBytes caps the total size of keys + values kept in memory (0 = unlimited)
+// least recently used entries are evicted first, a disk store still keeps them
+func WithMaxBytes(n int) Option {
+	return func(c *Cache) {
+		c.maxBytes = n
+	}
+}
+
+// entrySize is how many bytes an entry counts towards maxBytes
+func entrySize(key string, entry cacheEntry) int {
+	return len(key) + len(entry.val)
+}
+
+// touchLocked marks key as most recently used, caller MUST hold c.mu
+func (c *Cache) touchLocked(key string) {
+	// exist check (an oversized entry may have evicted itself)
+	if entry, ok := c.cache[key]; ok {
+		c.lru.MoveToFront(entry.elem)
+	}
+}
+
+// storeLocked adds or replaces an entry, then evicts until within limits, caller MUST hold c.mu
+func (c *Cache) storeLocked(key string, entry cacheEntry) {
+	// replacing check, drop the old one first so size stays right
+	if _, ok := c.cache[key]; ok {
+		c.removeLocked(key)
+	}
+
+	// oversized check, caching it would just flush everything else out
+	if c.maxBytes > 0 && entrySize(key, entry) > c.maxBytes {
+		return
+	}
+
+	// add as most recently used
+	entry.elem = c.lru.PushFront(key)
+	c.cache[key] = entry
+	c.bytes += entrySize(key, entry)
+	c.rawBytes += rawEntrySize(key, entry)
+
+	// evict least recently used until we're within limits
+	for c.overLimitLocked() && c.lru.Len() > 0 {
+		oldest := c.lru.Back()
+		c.removeLocked(oldest.Value.(string))
+		c.stats.Evictions++
+	}
+}
+
+// removeLocked deletes an entry from memory (not disk), caller MUST hold c.mu
+func (c *Cache) removeLocked(key string) {
+	entry, ok := c.cache[key]
+
+	// exist check
+	if !ok {
+		return
+	}
+
+	c.lru.Remove(entry.elem)
+	c.bytes -= entrySize(key, entry)
+	c.rawBytes -= rawEntrySize(key, entry)
+	delete(c.cache, key)
+}
+
+// overLimitLocked reports whether the cache is over either limit, caller MUST hold c.mu
+func (c *Cache) overLimitLocked() bool {
+	if c.maxEntries > 0 && len(c.cache) > c.maxEntries {
+		return true
+	}
+	if c.maxBytes > 0 && c.bytes > c.maxBytes {
+		return true
+	}
+	return false
+}
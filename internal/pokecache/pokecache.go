@@ -3,16 +3,31 @@
 package pokecache
 
 import (
+	"container/list" // for LRU order
+	"errors"         // for ErrClosed
 	"fmt"
 	"sync" // for mutex concurrency (maps aren't thread safe)
 	"time" // required for Timer functions
 )
 
+// ErrClosed is returned by CacheAdd/CacheGet once the cache has been closed
+// capped (public) so callers can check it with errors.Is
+var ErrClosed = errors.New("cache is closed")
+
 // cache entries struct for time created and raw data
 // lowercase (private) as its internal use only
 type cacheEntry struct {
-	createdAt time.Time // time at which cache entry was created
-	val       []byte    // raw data storage
+	createdAt time.Time     // time at which cache entry was created
+	val       []byte        // raw data storage
+	elem      *list.Element // position in the LRU list (value is the key)
+	ttl       time.Duration // how long this entry stays fresh (0 = cache interval)
+
+	validators Validators // ETag/Last-Modified for conditional refreshes (zero = none)
+
+	compressed bool // val is gzipped (see WithCompression)
+	rawSize    int  // len of the original val when compressed
+
+	decoded any // val decoded by a TypedCache, memory only (not counted towards maxBytes)
 }
 
 // cache entries map, mutex for map concurrency and reaper duration
@@ -21,17 +36,72 @@ type Cache struct {
 	cache    map[string]cacheEntry // map of cache entries
 	mu       *sync.Mutex           // mutex since maps aren't thread safe (must init in constructor as its ptr)
 	interval time.Duration         // store the duration here, which NewCache accepts as param and stores here
+	disk     *diskStore            // optional on-disk store (nil = memory only)
+
+	// LRU eviction, all guarded by mu
+	lru        *list.List // keys, most recently used at the front
+	bytes      int        // current size of keys + values in memory
+	maxEntries int        // entry cap (0 = unlimited)
+	maxBytes   int        // byte cap (0 = unlimited)
+	rawBytes   int        // what bytes would be without compression, for the ratio
+
+	// values at least this big are gzipped (0 = off)
+	compressMin int
+
+	// stale-while-revalidate, expired entries are kept (and served as stale) this much longer
+	maxStale time.Duration // 0 = off, entries are gone once expired
+
+	// expired entries with validators are kept this much longer so they can be renewed by a 304
+	revalidateWindow time.Duration // 0 = off
+
+	// counters for Stats, guarded by mu
+	stats CacheStats
+
+	// lifetime
+	closed     bool          // set by Close, guarded by mu
+	done       chan struct{} // closed by Close to stop the reaper
+	reaperDone chan struct{} // closed by the reaper once it has exited
 }
 
 // constructor function for making new cache
-// takes interval as arg, inits new cache, starts reaper goroutine and returns the cache
+// takes interval as arg (plus options), inits new cache, starts reaper goroutine and returns the cache
 // capped (public) for exposing to other packages
-func NewCache(interval time.Duration) *Cache { // ptr = more efficient, no data copying when passing
+func NewCache(interval time.Duration, opts ...Option) *Cache { // ptr = more efficient, no data copying when passing
+	return newCache(interval, nil, opts) // memory only
+}
+
+// constructor function for making new disk-backed cache
+// same as NewCache, but entries are also written to dir and survive restarts
+// entries on disk still expire after interval (using their stored createdAt)
+func NewPersistentCache(interval time.Duration, dir string, opts ...Option) (*Cache, error) {
+	// init the disk store first so we don't start a reaper for a broken cache
+	disk, err := newDiskStore(dir)
+
+	// disk store check
+	if err != nil {
+		return nil, err // early return
+	}
+
+	return newCache(interval, disk, opts), nil
+}
+
+// shared constructor, disk may be nil
+func newCache(interval time.Duration, disk *diskStore, opts []Option) *Cache {
 	cache := &Cache{
-		cache:    make(map[string]cacheEntry), // inits new cache
-		mu:       &sync.Mutex{},               // inits the mutex (safe, avoid nil ptr deref)
-		interval: interval,                    // takes the interval and stores in cache return
+		cache:      make(map[string]cacheEntry), // inits new cache
+		mu:         &sync.Mutex{},               // inits the mutex (safe, avoid nil ptr deref)
+		interval:   interval,                    // takes the interval and stores in cache return
+		disk:       disk,                        // on-disk store (nil = memory only)
+		lru:        list.New(),                  // inits the LRU order
+		done:       make(chan struct{}),         // stop signal for the reaper
+		reaperDone: make(chan struct{}),         // reaper exit signal for Close
+	}
+
+	// apply options in order
+	for _, opt := range opts {
+		opt(cache)
 	}
+
 	go cache.reapLoop() // starts "reaper" goroutine
 	return cache        // return the cache
 }
@@ -43,14 +113,19 @@ func (c *Cache) reapLoop() {
 	// init the time ticker and goroutine
 	ticker := time.NewTicker(c.interval) // init new ticker with age limit duration
 	defer ticker.Stop()                  // makes ticker stop on function loop ending
+	defer close(c.reaperDone)            // tell Close we're gone
 
-	// infinite reapLoop based on timer
+	// reapLoop based on timer, until Close
 	for {
-		// wait for the next tick
+		// wait for the next tick (or Close)
 		// this blocks until the ticker sends the next value on the channel
-		<-ticker.C // time.Ticker has its own channel called C
-		// <- = reads from the ticker channel, which sends the current time at regular intervals
-		// we set the "tick" to be c.interval, which is the cache age limit
+		select {
+		case <-ticker.C: // time.Ticker has its own channel called C
+			// <- = reads from the ticker channel, which sends the current time at regular intervals
+			// we set the "tick" to be c.interval, which is the cache age limit
+		case <-c.done:
+			return // cache closed, stop reaping
+		}
 
 		// lock mutex before accessing map
 		c.mu.Lock()
@@ -58,51 +133,97 @@ func (c *Cache) reapLoop() {
 
 		// loop thru cacheEntry keys and their values
 		for k, v := range c.cache {
-			ageCache := v.createdAt // get key-value age of cache
-			ageLimit := c.interval  // set simpel var for readability
-
-			// reap check based on cache age
-			if time.Since(ageCache) >= ageLimit {
-				delete(c.cache, k) // delete the cache entry by it's createdAt time
+			// reap check based on cache age (kept a while past its ttl when serving stale)
+			// entries only kept for revalidation never stay in memory, the disk store keeps those
+			if c.pastStaleLocked(v, time.Now()) {
+				c.removeLocked(k) // delete the cache entry by it's createdAt time (and its LRU spot)
+				c.stats.Expired++
 			}
 		}
 
 		// unlock mutex after accessing map
 		c.mu.Unlock()
+
+		// reap disk entries too (file IO outside the lock, it's slow, only the check takes it)
+		if c.disk != nil {
+			c.disk.reap(func(entry cacheEntry, now time.Time) bool {
+				c.mu.Lock()
+				defer c.mu.Unlock()
+				return c.reapableLocked(entry, now)
+			})
+		}
 	}
 }
 
 // cache add function -- adds a new entry to the cache
 // takes *Cache -- update the actual cache map NOT a copy
-// takes a URL-key:DATA-value pair as input
+// takes a URL-key:DATA-value pair as input, entry stays fresh for the cache interval
 func (c *Cache) CacheAdd(key string, val []byte) error { // returns new cache
 	// nil ptr check
 	if c == nil {
 		return fmt.Errorf("CacheAdd called with nil receiver") // early return
 	} // runtime panic if try access ptr fields, no memory location!
 
-	// get inputs (just for readability)
-	url := key  // location url as map key (identifies the entry)
-	data := val // location data as map key value
+	return c.CacheAddTTL(key, val, 0) // 0 = cache interval
+}
+
+// cache add with ttl function -- adds a new entry that stays fresh for ttl
+// takes a URL-key:DATA-value pair and ttl as input (0 = cache interval)
+func (c *Cache) CacheAddTTL(key string, val []byte, ttl time.Duration) error {
+	// nil ptr check
+	if c == nil {
+		return fmt.Errorf("CacheAddTTL called with nil receiver") // early return
+	} // runtime panic if try access ptr fields, no memory location!
+
+	return c.CacheAddValidated(key, val, ttl, Validators{}) // no validators, can't be revalidated
+}
+
+// add builds a new entry and stores it, every Cache/TypedCache add ends up here
+// decoded is the TypedCache's decoded val (nil for plain byte adds)
+func (c *Cache) add(key string, val []byte, ttl time.Duration, validators Validators, decoded any) error {
+	// create new cache entry (compressed BEFORE locking, gzip is slow)
+	entry := c.compress(cacheEntry{
+		createdAt:  time.Now(),
+		val:        val,
+		ttl:        ttl,
+		validators: validators,
+		decoded:    decoded,
+	})
 
 	// lock mutex before accessing map
 	c.mu.Lock()
 	defer c.mu.Unlock() // will unlock on *Cache return
 
-	// create new cache entry
-	entry := cacheEntry{
-		createdAt: time.Now(), // time.Now() = get current time for createdAt
-		val:       data,
+	// closed check
+	if c.closed {
+		return ErrClosed
 	}
+	return c.putLocked(key, entry)
+}
+
+// putLocked stores entry in memory and writes it through to disk, caller MUST hold c.mu
+func (c *Cache) putLocked(key string, entry cacheEntry) error {
+	// get inputs (just for readability)
+	url := key // location url as map key (identifies the entry)
+
+	// update existing cacheEntry map (evicts least recently used entries if over the limits)
+	c.storeLocked(url, entry) // fetches the whole struct and updates timestamp and data
+
+	// write through to disk if persistent
+	if c.disk != nil {
+		err := c.disk.put(url, entry)
 
-	// update existing cacheEntry map
-	c.cache[url] = entry // fetches the whole struct and updates timestamp and data
+		// disk write check (entry is still cached in memory)
+		if err != nil {
+			return fmt.Errorf("error persisting cache entry: %w", err)
+		}
+	}
 
 	// successfully  added new cache entry
 	return nil
 }
 
-// cache get function -- gets an existing entry from the cache
+// cache get function -- gets an existing FRESH entry from the cache
 // takes *Cache -- returns a []byte and "found" bool
 // takes a URL-key as input
 func (c *Cache) CacheGet(key string) ([]byte, bool, error) { // returns existing cache
@@ -111,22 +232,96 @@ func (c *Cache) CacheGet(key string) ([]byte, bool, error) { // returns existing
 		return nil, false, fmt.Errorf("CacheGet called with nil receiver") // early return
 	} // runtime panic if try access ptr fields, no memory location!
 
-	// get inputs (just for readability)
-	url := key // location url as map key (identifies the entry)
-
 	// lock mutex before accessing map
 	c.mu.Lock()
 	defer c.mu.Unlock() // will unlock on *Cache return
 
+	// closed check
+	if c.closed {
+		return nil, false, ErrClosed
+	}
+
+	// look it up, stale entries don't count here
+	entry, ok := c.getLocked(key)
+	if !ok || c.expiredLocked(entry, time.Now()) {
+		c.stats.Misses++
+		return nil, false, nil // not found, no error
+	}
+	c.stats.Hits++
+
+	// otherwise, found entry and return as success
+	data, err := entry.value() // get entry's val field, []byte (decompressed)
+
+	// decompress check
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// getLocked finds key in memory, then on disk, caller MUST hold c.mu
+// returns fresh AND stale entries (callers decide), marks the entry recently used
+func (c *Cache) getLocked(key string) (cacheEntry, bool) {
+	// get inputs (just for readability)
+	url := key // location url as map key (identifies the entry)
+
 	// loop thru cache to see if url can be found
 	entry, ok := c.cache[url]
 
+	// not in memory, try disk if persistent (cold start)
+	if !ok && c.disk != nil {
+		entry, ok = c.disk.get(url)
+
+		// too old on disk check (reaper may not have run yet)
+		if ok && c.reapableLocked(entry, time.Now()) {
+			c.disk.remove(url) // drop it, caller will refetch
+			ok = false
+		}
+
+		// found on disk, keep in memory with its ORIGINAL createdAt so TTL still holds
+		if ok {
+			c.storeLocked(url, entry)
+		}
+	}
+
 	// exist check
 	if !ok {
-		return nil, false, nil // not found, no error
+		return cacheEntry{}, false
 	}
 
-	// otherwise, found entry and return as success
-	data := entry.val // get entry's val field, []byte
-	return data, true, nil
+	// mark it recently used
+	c.touchLocked(url)
+	return entry, true
+}
+
+// cache close function -- stops the reaper goroutine and flushes the disk store
+// further CacheAdd/CacheGet calls return ErrClosed, closing twice is a no-op
+func (c *Cache) Close() error {
+	// nil ptr check
+	if c == nil {
+		return fmt.Errorf("Close called with nil receiver") // early return
+	} // runtime panic if try access ptr fields, no memory location!
+
+	// lock mutex before touching closed
+	c.mu.Lock()
+
+	// already closed check
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+
+	// mark closed and stop the reaper
+	c.closed = true
+	close(c.done)
+	c.mu.Unlock() // unlock BEFORE waiting, the reaper may be waiting for the lock
+
+	// wait for the reaper to exit so no goroutine or ticker outlives the cache
+	<-c.reaperDone
+
+	// flush disk store so every written entry survives a crash
+	if c.disk != nil {
+		return c.disk.flush()
+	}
+	return nil
 }
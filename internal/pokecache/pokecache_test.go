@@ -2,7 +2,9 @@
 package pokecache
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -28,6 +30,7 @@ func TestAddGet(t *testing.T) {
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
 			cache := NewCache(interval)
+			defer cache.Close()
 			cache.CacheAdd(c.key, c.val)
 			val, ok, err := cache.CacheGet(c.key)
 			if err != nil {
@@ -50,6 +53,7 @@ func TestReapLoop(t *testing.T) {
 	const baseTime = 5 * time.Millisecond
 	const waitTime = baseTime + 5*time.Millisecond
 	cache := NewCache(baseTime)
+	defer cache.Close()
 	cache.CacheAdd("https://example.com", []byte("testdata"))
 
 	_, ok, err := cache.CacheGet("https://example.com")
@@ -74,3 +78,435 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 }
+
+func TestPersistentCache(t *testing.T) {
+	const interval = 5 * time.Second
+	dir := t.TempDir()
+
+	cache, err := NewPersistentCache(interval, dir)
+	if err != nil {
+		t.Fatalf("NewPersistentCache unsuccesful: %v", err)
+	}
+	defer cache.Close()
+	if err := cache.CacheAdd("https://example.com", []byte("testdata")); err != nil {
+		t.Fatalf("CacheAdd unsuccesful: %v", err)
+	}
+
+	// a second cache on the same dir simulates a cold start
+	cold, err := NewPersistentCache(interval, dir)
+	if err != nil {
+		t.Fatalf("NewPersistentCache unsuccesful: %v", err)
+	}
+	defer cold.Close()
+	val, ok, err := cold.CacheGet("https://example.com")
+	if err != nil {
+		t.Errorf("CacheGet unsuccesful")
+		return
+	}
+	if !ok {
+		t.Errorf("expected to find key on disk")
+		return
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected to find value")
+	}
+}
+
+func TestPersistentCacheExpired(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	dir := t.TempDir()
+
+	cache, err := NewPersistentCache(time.Hour, dir)
+	if err != nil {
+		t.Fatalf("NewPersistentCache unsuccesful: %v", err)
+	}
+	defer cache.Close()
+	cache.CacheAdd("https://example.com", []byte("testdata"))
+
+	time.Sleep(baseTime + 5*time.Millisecond)
+
+	// same dir, much shorter TTL: the stored createdAt is now too old
+	cold, err := NewPersistentCache(baseTime, dir)
+	if err != nil {
+		t.Fatalf("NewPersistentCache unsuccesful: %v", err)
+	}
+	defer cold.Close()
+	_, ok, err := cold.CacheGet("https://example.com")
+	if err != nil {
+		t.Errorf("CacheGet unsuccesful")
+		return
+	}
+	if ok {
+		t.Errorf("expected expired disk entry to be ignored")
+	}
+}
+
+func TestLRUMaxEntries(t *testing.T) {
+	cache := NewCache(time.Minute, WithMaxEntries(2))
+	defer cache.Close()
+	cache.CacheAdd("a", []byte("1"))
+	cache.CacheAdd("b", []byte("2"))
+
+	// touch "a" so "b" becomes least recently used
+	cache.CacheGet("a")
+	cache.CacheAdd("c", []byte("3"))
+
+	cases := map[string]bool{"a": true, "b": false, "c": true}
+	for key, expected := range cases {
+		_, ok, err := cache.CacheGet(key)
+		if err != nil {
+			t.Errorf("CacheGet unsuccesful")
+			return
+		}
+		if ok != expected {
+			t.Errorf("key %s: expected found=%v, got %v", key, expected, ok)
+		}
+	}
+}
+
+func TestLRUMaxBytes(t *testing.T) {
+	// each entry is 1 byte key + 4 byte value = 5 bytes
+	cache := NewCache(time.Minute, WithMaxBytes(10))
+	defer cache.Close()
+	cache.CacheAdd("a", []byte("aaaa"))
+	cache.CacheAdd("b", []byte("bbbb"))
+	cache.CacheAdd("c", []byte("cccc"))
+
+	if _, ok, _ := cache.CacheGet("a"); ok {
+		t.Errorf("expected oldest entry to be evicted")
+	}
+	if _, ok, _ := cache.CacheGet("c"); !ok {
+		t.Errorf("expected newest entry to be kept")
+	}
+
+	// bigger than the whole cap, never kept in memory
+	cache.CacheAdd("huge", []byte("way more than ten bytes"))
+	if _, ok, _ := cache.CacheGet("huge"); ok {
+		t.Errorf("expected oversized entry to not be cached")
+	}
+	if _, ok, _ := cache.CacheGet("c"); !ok {
+		t.Errorf("expected oversized entry to not flush the rest")
+	}
+}
+
+func TestClose(t *testing.T) {
+	cache, err := NewPersistentCache(time.Millisecond, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewPersistentCache unsuccesful: %v", err)
+	}
+	cache.CacheAdd("https://example.com", []byte("testdata"))
+
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close unsuccesful: %v", err)
+	}
+	if err := cache.Close(); err != nil {
+		t.Errorf("expected second Close to be a no-op, got %v", err)
+	}
+
+	if err := cache.CacheAdd("https://example.com", []byte("testdata")); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed from CacheAdd, got %v", err)
+	}
+	if _, _, err := cache.CacheGet("https://example.com"); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed from CacheGet, got %v", err)
+	}
+
+	// reaper must have exited (Close waits for it)
+	select {
+	case <-cache.reaperDone:
+	default:
+		t.Errorf("expected reaper goroutine to be stopped")
+	}
+}
+
+func TestStats(t *testing.T) {
+	cache := NewCache(time.Minute, WithMaxEntries(1))
+	defer cache.Close()
+
+	cache.CacheAdd("a", []byte("1"))
+	cache.CacheGet("a")               // hit
+	cache.CacheGet("missing")         // miss
+	cache.CacheAdd("b", []byte("22")) // evicts "a"
+
+	stats := cache.Stats()
+	expected := CacheStats{Hits: 1, Misses: 1, Evictions: 1, Entries: 1, Bytes: 3, RawBytes: 3}
+	if stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+
+	if found, err := cache.Evict("b"); err != nil || !found {
+		t.Errorf("expected to evict b, got found=%v err=%v", found, err)
+	}
+	if entries, _ := cache.Entries(); len(entries) != 0 {
+		t.Errorf("expected no entries after evict, got %d", len(entries))
+	}
+}
+
+func TestClearPersistent(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewPersistentCache(time.Minute, dir)
+	if err != nil {
+		t.Fatalf("NewPersistentCache unsuccesful: %v", err)
+	}
+	defer cache.Close()
+
+	cache.CacheAdd("https://example.com", []byte("testdata"))
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear unsuccesful: %v", err)
+	}
+
+	// cleared from disk too, a cold start must not find it
+	cold, err := NewPersistentCache(time.Minute, dir)
+	if err != nil {
+		t.Fatalf("NewPersistentCache unsuccesful: %v", err)
+	}
+	defer cold.Close()
+	if _, ok, _ := cold.CacheGet("https://example.com"); ok {
+		t.Errorf("expected cleared entry to be gone from disk")
+	}
+}
+
+func TestCacheAddTTL(t *testing.T) {
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+
+	cache.CacheAddTTL("short", []byte("testdata"), 5*time.Millisecond)
+	cache.CacheAdd("default", []byte("testdata"))
+	time.Sleep(10 * time.Millisecond)
+
+	if _, ok, _ := cache.CacheGet("short"); ok {
+		t.Errorf("expected short ttl entry to be expired")
+	}
+	if _, ok, _ := cache.CacheGet("default"); !ok {
+		t.Errorf("expected default ttl entry to still be fresh")
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	cache := NewCache(time.Minute, WithStaleWhileRevalidate(time.Minute))
+	defer cache.Close()
+
+	cache.CacheAddTTL("https://example.com", []byte("testdata"), 5*time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+
+	// CacheGet never hands out stale data
+	if _, ok, _ := cache.CacheGet("https://example.com"); ok {
+		t.Errorf("expected CacheGet to skip stale entry")
+	}
+
+	val, ok, stale, err := cache.CacheGetStale("https://example.com")
+	if err != nil || !ok || !stale || string(val) != "testdata" {
+		t.Errorf("expected stale testdata, got ok=%v stale=%v err=%v", ok, stale, err)
+	}
+
+	// without the option, expired is gone
+	plain := NewCache(time.Minute)
+	defer plain.Close()
+	plain.CacheAddTTL("https://example.com", []byte("testdata"), 5*time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	if _, ok, _, _ := plain.CacheGetStale("https://example.com"); ok {
+		t.Errorf("expected expired entry without stale serving to be a miss")
+	}
+}
+
+func TestRevalidateWindow(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewPersistentCache(time.Minute, dir, WithRevalidateWindow(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cache.Close()
+
+	validators := Validators{ETag: `"abc"`, LastModified: "Mon, 01 Jan 2024 00:00:00 GMT"}
+	cache.CacheAddValidated("key", []byte("testdata"), 5*time.Millisecond, validators)
+	time.Sleep(10 * time.Millisecond)
+
+	// expired entries are never served...
+	if _, ok, _ := cache.CacheGet("key"); ok {
+		t.Errorf("expected expired entry to miss")
+	}
+	if _, ok, _, _ := cache.CacheGetStale("key"); ok {
+		t.Errorf("expected expired entry to miss without stale serving")
+	}
+
+	// ...but are kept (with validators, on disk too) for a conditional refresh
+	cold, err := NewPersistentCache(time.Minute, dir, WithRevalidateWindow(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cold.Close()
+	val, got, ok, _ := cold.CacheGetValidators("key")
+	if !ok || string(val) != "testdata" || got != validators {
+		t.Fatalf("expected expired entry with validators %+v, got %q %+v (%v)", validators, val, got, ok)
+	}
+
+	// a 304 renews it
+	if renewed, err := cache.CacheRenew("key", time.Minute); !renewed || err != nil {
+		t.Fatalf("expected renewal, got %v (%v)", renewed, err)
+	}
+	if val, ok, _ := cache.CacheGet("key"); !ok || string(val) != "testdata" {
+		t.Errorf("expected renewed entry to be fresh, got %q (%v)", val, ok)
+	}
+	if stats := cache.Stats(); stats.Renewals != 1 {
+		t.Errorf("expected 1 renewal, got %d", stats.Renewals)
+	}
+
+	// nothing to renew
+	if renewed, _ := cache.CacheRenew("missing", 0); renewed {
+		t.Errorf("expected missing entry not to be renewed")
+	}
+}
+
+func TestRevalidateWindowFreesMemory(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewPersistentCache(10*time.Millisecond, dir, WithRevalidateWindow(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cache.Close()
+
+	validators := Validators{ETag: `"abc"`}
+	cache.CacheAddValidated("key", []byte("testdata"), 5*time.Millisecond, validators)
+	time.Sleep(50 * time.Millisecond) // a few reaper ticks
+
+	// reaped from memory like any expired entry...
+	if stats := cache.Stats(); stats.Entries != 0 || stats.Expired != 1 {
+		t.Errorf("expected the expired entry reaped from memory, got %+v", stats)
+	}
+
+	// ...but still on disk for a conditional refresh
+	if _, got, ok, _ := cache.CacheGetValidators("key"); !ok || got != validators {
+		t.Errorf("expected expired entry with validators on disk, got %+v (%v)", got, ok)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewPersistentCache(time.Minute, dir, WithCompression(100))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cache.Close()
+
+	big := []byte(strings.Repeat(`{"name": "pikachu"},`, 100)) // very compressible
+	cache.CacheAdd("big", big)
+	cache.CacheAdd("small", []byte("testdata"))
+
+	// values come back as they went in
+	if val, ok, _ := cache.CacheGet("big"); !ok || string(val) != string(big) {
+		t.Errorf("expected big value back uncompressed")
+	}
+	if val, ok, _ := cache.CacheGet("small"); !ok || string(val) != "testdata" {
+		t.Errorf("expected small value back, got %q", val)
+	}
+
+	// only the big one is compressed, and it counts at its compressed size
+	stats := cache.Stats()
+	if stats.Compressed != 1 {
+		t.Errorf("expected 1 compressed entry, got %d", stats.Compressed)
+	}
+	if stats.Bytes >= stats.RawBytes || stats.RawBytes != len("big")+len(big)+len("small")+len("testdata") {
+		t.Errorf("expected compressed size below raw size %d, got %d", stats.RawBytes, stats.Bytes)
+	}
+
+	// compressed entries survive a restart
+	cold, err := NewPersistentCache(time.Minute, dir, WithCompression(100))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cold.Close()
+	if val, ok, _ := cold.CacheGet("big"); !ok || string(val) != string(big) {
+		t.Errorf("expected big value back from disk uncompressed")
+	}
+}
+
+func TestTypedCache(t *testing.T) {
+	cache := NewCache(time.Minute, WithCompression(1))
+	defer cache.Close()
+
+	// counts decodes so we can see them being skipped
+	decodes := 0
+	typed := NewTypedCache(cache, func(raw []byte) (string, error) {
+		decodes++
+		if string(raw) == "junk" {
+			return "", errors.New("bad value")
+		}
+		return strings.ToUpper(string(raw)), nil
+	})
+
+	// added as bytes: decoded on the first hit only
+	cache.CacheAdd("raw", []byte("testdata"))
+	for i := 0; i < 3; i++ {
+		if val, ok, err := typed.Get("raw"); !ok || err != nil || val != "TESTDATA" {
+			t.Fatalf("expected TESTDATA, got %q (%v, %v)", val, ok, err)
+		}
+	}
+	if decodes != 1 {
+		t.Errorf("expected 1 decode, got %d", decodes)
+	}
+
+	// added with its decoded value: never decoded
+	typed.Add("typed", []byte("testdata"), "DECODED", 0, Validators{})
+	if val, ok, _ := typed.Get("typed"); !ok || val != "DECODED" {
+		t.Errorf("expected kept value DECODED, got %q (%v)", val, ok)
+	}
+	if decodes != 1 {
+		t.Errorf("expected no extra decode, got %d", decodes)
+	}
+
+	// the byte api still sees the raw value
+	if val, ok, _ := cache.CacheGet("typed"); !ok || string(val) != "testdata" {
+		t.Errorf("expected raw testdata, got %q (%v)", val, ok)
+	}
+
+	// decode errors come back, misses are just misses
+	cache.CacheAdd("junk", []byte("junk"))
+	if _, _, err := typed.Get("junk"); err == nil {
+		t.Errorf("expected decode error")
+	}
+	if _, ok, err := typed.Get("missing"); ok || err != nil {
+		t.Errorf("expected plain miss, got %v (%v)", ok, err)
+	}
+
+	if stats := cache.Stats(); stats.DecodedHits != 3 {
+		t.Errorf("expected 3 decoded hits, got %d", stats.DecodedHits)
+	}
+}
+
+func TestTypedCacheDecodesUnlocked(t *testing.T) {
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+
+	// decode blocks until we say so
+	decoding := make(chan struct{})
+	release := make(chan struct{})
+	typed := NewTypedCache(cache, func(raw []byte) (string, error) {
+		close(decoding)
+		<-release
+		return string(raw), nil
+	})
+	cache.CacheAdd("slow", []byte("testdata"))
+
+	done := make(chan string)
+	go func() {
+		val, _, _ := typed.Get("slow")
+		done <- val
+	}()
+	<-decoding
+
+	// the rest of the cache keeps working while it decodes
+	added := make(chan error)
+	go func() { added <- cache.CacheAdd("other", []byte("testdata")) }()
+	select {
+	case err := <-added:
+		if err != nil {
+			t.Errorf("CacheAdd unsuccesful: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CacheAdd blocked behind a decode")
+	}
+
+	close(release)
+	if val := <-done; val != "testdata" {
+		t.Errorf("expected testdata, got %q", val)
+	}
+}
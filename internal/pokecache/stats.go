@@ -0,0 +1,152 @@
+// internal/pokecache/stats.go
+// for seeing whether the cache is actually helping
+package pokecache
+
+import (
+	"fmt"
+	"sort" // for stable listing order
+	"time" // for entry ages
+)
+
+// CacheStats is a snapshot of cache counters
+// capped (public) for exposing to other packages
+type CacheStats struct {
+	Hits      int // lookups served from memory or disk
+	StaleHits int // lookups served an expired entry (stale-while-revalidate)
+	Misses    int // lookups that had to go to the network
+	Evictions int // entries dropped by the LRU limits
+	Expired   int // entries dropped by the reaper (TTL)
+	Renewals  int // expired entries renewed by a 304 instead of a full download
+
+	DecodedHits int // TypedCache hits served an already decoded value (no decoding)
+	Entries     int // entries currently in memory
+	Bytes       int // size of keys + values currently in memory (compressed)
+
+	RawBytes   int // what Bytes would be uncompressed, RawBytes/Bytes is the compression ratio
+	Compressed int // entries currently stored compressed
+}
+
+// EntryInfo describes one in-memory cache entry for listing
+type EntryInfo struct {
+	Key       string    // cache key (request url)
+	Size      int       // bytes counted towards the limits
+	CreatedAt time.Time // when it was fetched
+}
+
+// cache stats function -- returns a snapshot of the counters
+func (c *Cache) Stats() CacheStats {
+	// nil ptr check
+	if c == nil {
+		return CacheStats{} // nothing to report
+	}
+
+	// lock mutex before reading counters
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats // copy counters
+	stats.Entries = len(c.cache)
+	stats.Bytes = c.bytes
+	stats.RawBytes = c.rawBytes
+	for _, entry := range c.cache {
+		if entry.compressed {
+			stats.Compressed++
+		}
+	}
+	return stats
+}
+
+// cache entries function -- lists in-memory entries, sorted by key
+func (c *Cache) Entries() ([]EntryInfo, error) {
+	// nil ptr check
+	if c == nil {
+		return nil, fmt.Errorf("Entries called with nil receiver") // early return
+	}
+
+	// lock mutex before accessing map
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// closed check
+	if c.closed {
+		return nil, ErrClosed
+	}
+
+	// collect entry info
+	entries := make([]EntryInfo, 0, len(c.cache))
+	for key, entry := range c.cache {
+		entries = append(entries, EntryInfo{Key: key, Size: entrySize(key, entry), CreatedAt: entry.createdAt})
+	}
+
+	// sorted so output is stable
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+// cache evict function -- removes one entry from memory AND disk
+// returns whether it was cached in memory
+func (c *Cache) Evict(key string) (bool, error) {
+	// nil ptr check
+	if c == nil {
+		return false, fmt.Errorf("Evict called with nil receiver") // early return
+	}
+
+	// lock mutex before accessing map
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// closed check
+	if c.closed {
+		return false, ErrClosed
+	}
+
+	// remove from memory
+	_, ok := c.cache[key]
+	c.removeLocked(key)
+
+	// remove from disk too, or the next get would just load it back
+	if c.disk != nil {
+		if err := c.disk.remove(key); err != nil {
+			return ok, err
+		}
+	}
+	return ok, nil
+}
+
+// cache clear function -- removes every entry from memory AND disk
+// counters are kept, they describe the whole session
+func (c *Cache) Clear() error {
+	// nil ptr check
+	if c == nil {
+		return fmt.Errorf("Clear called with nil receiver") // early return
+	}
+
+	// lock mutex before accessing map
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// closed check
+	if c.closed {
+		return ErrClosed
+	}
+
+	// drop everything in memory
+	for key := range c.cache {
+		c.removeLocked(key)
+	}
+
+	// and on disk
+	if c.disk != nil {
+		return c.disk.clear()
+	}
+	return nil
+}
+
+// countHitLocked counts a fresh or stale hit, caller MUST hold c.mu
+func (c *Cache) countHitLocked(stale bool) {
+	if stale {
+		c.stats.StaleHits++
+		return
+	}
+	c.stats.Hits++
+}
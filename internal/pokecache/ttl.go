@@ -0,0 +1,82 @@
+// internal/pokecache/ttl.go
+// for per-entry TTLs and serving stale entries while they're refreshed
+package pokecache
+
+import (
+	"fmt"
+	"time" // for ages and ttls
+)
+
+// WithStaleWhileRevalidate keeps expired entries for maxStale past their ttl
+// CacheGetStale hands them out (flagged stale) so callers can answer instantly and refresh in the background
+// CacheGet never returns stale entries
+func WithStaleWhileRevalidate(maxStale time.Duration) Option {
+	return func(c *Cache) {
+		c.maxStale = maxStale
+	}
+}
+
+// cache get stale function -- like CacheGet, but also returns expired entries kept by WithStaleWhileRevalidate
+// stale is true when the entry is past its ttl and should be refreshed
+func (c *Cache) CacheGetStale(key string) (val []byte, ok bool, stale bool, err error) {
+	// nil ptr check
+	if c == nil {
+		return nil, false, false, fmt.Errorf("CacheGetStale called with nil receiver") // early return
+	} // runtime panic if try access ptr fields, no memory location!
+
+	// lock mutex before accessing map
+	c.mu.Lock()
+	defer c.mu.Unlock() // will unlock on *Cache return
+
+	// closed check
+	if c.closed {
+		return nil, false, false, ErrClosed
+	}
+
+	// look it up
+	entry, ok := c.getLocked(key)
+	now := time.Now()
+
+	// not found (or expired past the stale window) check
+	if !ok || c.pastStaleLocked(entry, now) {
+		c.stats.Misses++
+		return nil, false, false, nil
+	}
+
+	// decompress the value
+	val, err = entry.value()
+
+	// decompress check
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	// stale check
+	stale = c.expiredLocked(entry, now)
+	c.countHitLocked(stale)
+	return val, true, stale, nil
+}
+
+// ttlLocked returns how long entry stays fresh, caller MUST hold c.mu
+func (c *Cache) ttlLocked(entry cacheEntry) time.Duration {
+	// no per-entry ttl, use the cache interval
+	if entry.ttl <= 0 {
+		return c.interval
+	}
+	return entry.ttl
+}
+
+// expiredLocked reports whether entry is past its ttl (stale), caller MUST hold c.mu
+func (c *Cache) expiredLocked(entry cacheEntry, now time.Time) bool {
+	return now.Sub(entry.createdAt) >= c.ttlLocked(entry)
+}
+
+// pastStaleLocked reports whether entry is past its ttl AND the stale window (can't be served), caller MUST hold c.mu
+func (c *Cache) pastStaleLocked(entry cacheEntry, now time.Time) bool {
+	return now.Sub(entry.createdAt) >= c.ttlLocked(entry)+c.maxStale
+}
+
+// reapableLocked reports whether entry is past its ttl AND every window it's kept for on disk, caller MUST hold c.mu
+func (c *Cache) reapableLocked(entry cacheEntry, now time.Time) bool {
+	return now.Sub(entry.createdAt) >= c.ttlLocked(entry)+c.keepLocked(entry)
+}
@@ -0,0 +1,118 @@
+// internal/pokecache/typed.go
+// for caching decoded values next to the raw bytes, so hits skip decoding
+package pokecache
+
+import (
+	"fmt"
+	"time" // for ttls
+)
+
+// TypedCache is a typed view of a Cache: values go in and come out as T
+// the raw bytes are still what's stored (and persisted), the decoded T is kept alongside
+// in memory so repeated hits don't decode again (first hit after a restart decodes once)
+// values handed out are shared between callers, treat them as read-only
+type TypedCache[T any] struct {
+	cache  *Cache                  // the byte-level cache underneath
+	decode func([]byte) (T, error) // raw bytes -> T, on the first hit of each entry
+}
+
+// NewTypedCache returns a typed view of cache, decode turns stored bytes into T
+// several typed views (of different T) can share one Cache
+func NewTypedCache[T any](cache *Cache, decode func([]byte) (T, error)) TypedCache[T] {
+	return TypedCache[T]{cache: cache, decode: decode}
+}
+
+// Get is CacheGet, decoded
+func (tc TypedCache[T]) Get(key string) (T, bool, error) {
+	val, ok, _, err := tc.get(key, false) // fresh only, so never stale
+	return val, ok, err
+}
+
+// GetStale is CacheGetStale, decoded
+func (tc TypedCache[T]) GetStale(key string) (val T, ok bool, stale bool, err error) {
+	return tc.get(key, true)
+}
+
+// Add is CacheAddValidated, keeping val (raw decoded) so the first hit doesn't decode either
+func (tc TypedCache[T]) Add(key string, raw []byte, val T, ttl time.Duration, validators Validators) error {
+	// nil ptr check
+	if tc.cache == nil {
+		return fmt.Errorf("TypedCache.Add called with nil cache") // early return
+	}
+
+	return tc.cache.add(key, raw, ttl, validators, val)
+}
+
+// get looks key up and returns it decoded, from the kept value if there is one
+// decoding happens OUTSIDE the lock (it's slow), so other cache users never wait on it
+func (tc TypedCache[T]) get(key string, allowStale bool) (val T, ok bool, stale bool, err error) {
+	var zero T
+
+	// nil ptr check
+	if tc.cache == nil {
+		return zero, false, false, fmt.Errorf("TypedCache.Get called with nil cache") // early return
+	}
+
+	c := tc.cache // for readability
+
+	// lock mutex before accessing map
+	c.mu.Lock()
+
+	// closed check
+	if c.closed {
+		c.mu.Unlock()
+		return zero, false, false, ErrClosed
+	}
+
+	// look it up
+	entry, ok := c.getLocked(key)
+	now := time.Now()
+
+	// not found, too old to serve, or stale when stale isn't wanted
+	stale = ok && c.expiredLocked(entry, now)
+	if !ok || c.pastStaleLocked(entry, now) || (stale && !allowStale) {
+		c.stats.Misses++
+		c.mu.Unlock()
+		return zero, false, false, nil
+	}
+
+	// already decoded check (another T for the same key gets decoded again)
+	if decoded, isT := entry.decoded.(T); isT {
+		c.countHitLocked(stale)
+		c.stats.DecodedHits++
+		c.mu.Unlock()
+		return decoded, true, stale, nil
+	}
+
+	// unlock for the slow part, the entry's bytes are never changed in place
+	c.mu.Unlock()
+
+	// decode the raw bytes
+	raw, err := entry.value()
+
+	// decompress check
+	if err != nil {
+		return zero, false, false, err
+	}
+
+	val, err = tc.decode(raw)
+
+	// decode check
+	if err != nil {
+		return zero, false, false, err
+	}
+
+	// lock mutex again to count the hit and keep the value
+	c.mu.Lock()
+	defer c.mu.Unlock() // will unlock on *Cache return
+
+	// keep it for next time (size, ttl and LRU spot don't change)
+	// only if it's still the entry we decoded (not replaced, evicted or closed meanwhile)
+	// re-read from the map, an oversized entry loaded from disk may not be in memory at all
+	if kept, inMemory := c.cache[key]; inMemory && !c.closed && kept.createdAt.Equal(entry.createdAt) {
+		kept.decoded = val
+		c.cache[key] = kept
+	}
+	c.countHitLocked(stale)
+	return val, true, stale, nil
+}
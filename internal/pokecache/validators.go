@@ -0,0 +1,113 @@
+// internal/pokecache/validators.go
+// for keeping HTTP validators (ETag/Last-Modified) so expired entries can be renewed cheaply
+package pokecache
+
+import (
+	"fmt"
+	"time" // for renewal timestamps
+)
+
+// Validators are the HTTP cache validators a response came with
+// sent back as If-None-Match/If-Modified-Since, a 304 means the cached body is still good
+type Validators struct {
+	ETag         string // ETag response header
+	LastModified string // Last-Modified response header
+}
+
+// IsZero reports whether there is nothing to revalidate with
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// WithRevalidateWindow keeps expired entries that have validators for window past their ttl
+// they're never served (CacheGet/CacheGetStale treat them as misses), but CacheGetValidators
+// hands them out so the caller can make a conditional request and CacheRenew them on a 304
+// only the disk store keeps them (memory is still reaped after the stale window), so this
+// does nothing for a memory only cache
+func WithRevalidateWindow(window time.Duration) Option {
+	return func(c *Cache) {
+		c.revalidateWindow = window
+	}
+}
+
+// cache add validated function -- like CacheAddTTL, but also keeps the response's validators
+func (c *Cache) CacheAddValidated(key string, val []byte, ttl time.Duration, validators Validators) error {
+	// nil ptr check
+	if c == nil {
+		return fmt.Errorf("CacheAddValidated called with nil receiver") // early return
+	} // runtime panic if try access ptr fields, no memory location!
+
+	return c.add(key, val, ttl, validators, nil) // nothing decoded
+}
+
+// cache get validators function -- gets ANY cached entry for key (fresh, stale or expired) and its validators
+// for building a conditional request, so it doesn't count towards hits/misses
+func (c *Cache) CacheGetValidators(key string) (val []byte, validators Validators, ok bool, err error) {
+	// nil ptr check
+	if c == nil {
+		return nil, Validators{}, false, fmt.Errorf("CacheGetValidators called with nil receiver") // early return
+	} // runtime panic if try access ptr fields, no memory location!
+
+	// lock mutex before accessing map
+	c.mu.Lock()
+	defer c.mu.Unlock() // will unlock on *Cache return
+
+	// closed check
+	if c.closed {
+		return nil, Validators{}, false, ErrClosed
+	}
+
+	// look it up
+	entry, ok := c.getLocked(key)
+	if !ok {
+		return nil, Validators{}, false, nil // not found, no error
+	}
+
+	// decompress the value
+	val, err = entry.value()
+
+	// decompress check
+	if err != nil {
+		return nil, Validators{}, false, err
+	}
+	return val, entry.validators, true, nil
+}
+
+// cache renew function -- marks an existing entry as freshly fetched (the server said 304 Not Modified)
+// takes the new ttl (0 = cache interval), returns false if the entry is gone
+func (c *Cache) CacheRenew(key string, ttl time.Duration) (bool, error) {
+	// nil ptr check
+	if c == nil {
+		return false, fmt.Errorf("CacheRenew called with nil receiver") // early return
+	} // runtime panic if try access ptr fields, no memory location!
+
+	// lock mutex before accessing map
+	c.mu.Lock()
+	defer c.mu.Unlock() // will unlock on *Cache return
+
+	// closed check
+	if c.closed {
+		return false, ErrClosed
+	}
+
+	// look it up
+	entry, ok := c.getLocked(key)
+	if !ok {
+		return false, nil // reaped or evicted in the meantime, caller refetches
+	}
+
+	// same body, new lease of life
+	entry.createdAt = time.Now()
+	entry.ttl = ttl
+	c.stats.Renewals++
+	return true, c.putLocked(key, entry)
+}
+
+// keepLocked returns how long entry is kept past its ttl (on disk), caller MUST hold c.mu
+// the stale window, or the revalidate window if the entry can be revalidated and that's longer
+func (c *Cache) keepLocked(entry cacheEntry) time.Duration {
+	if !entry.validators.IsZero() && c.revalidateWindow > c.maxStale {
+		return c.revalidateWindow
+	}
+	return c.maxStale
+}
@@ -3,20 +3,208 @@ package main // all files in same folder form part of package main
 
 import (
 	// import standard Go libraries
-	"time" // for interval limit pass to cache
+	"errors"        // for checking load errors
+	"flag"          // for command line flags
+	"fmt"           // for printing load warnings
+	"io/fs"         // for missing save file check
+	"net/http"      // for the serve-mock subcommand
+	"os"            // for renaming corrupt saves
+	"path/filepath" // for building the default cache dir
+	"time"          // for interval limit pass to cache
 
 	// import internal packages
+	"github.com/PietPadda/pokedexcli/internal/mockapi"   // local PokeAPI stand-in
 	"github.com/PietPadda/pokedexcli/internal/pokeapi"   // pokeapi client package
 	"github.com/PietPadda/pokedexcli/internal/pokecache" // cache package
 )
 
 func main() {
+	// subcommand check: serve-mock runs a local PokeAPI instead of the REPL
+	if len(os.Args) > 1 && os.Args[1] == "serve-mock" {
+		serveMock(os.Args[2:])
+		return
+	}
+
+	// get default save path (XDG data dir), empty if it can't be determined
+	defaultSavePath, err := pokeapi.DefaultSavePath()
+	if err != nil {
+		fmt.Println("warning:", err) // still run, just without a default save
+	}
+
+	// get default api base url, env var overrides the public pokeapi
+	defaultBaseURL := os.Getenv("POKEAPI_BASE_URL")
+	if defaultBaseURL == "" {
+		defaultBaseURL = pokeapi.DefaultBaseURL
+	}
+
+	// parse command line flags
+	baseURL := flag.String("base-url", defaultBaseURL, "PokeAPI base url (or set POKEAPI_BASE_URL)")
+	savePath := flag.String("save", defaultSavePath, "path to the Pokedex save file (empty disables saving)")
+	cacheDir := flag.String("cache-dir", defaultCacheDir(), "directory for the on-disk response cache (empty keeps the cache in memory only)")
+	cacheTTL := flag.Duration("cache-ttl", 5*time.Minute, "how long cached responses stay valid")
+	cacheMaxEntries := flag.Int("cache-max-entries", 0, "max responses kept in memory, least recently used evicted first (0 = unlimited)")
+	cacheMaxBytes := flag.Int("cache-max-bytes", 0, "max bytes of responses kept in memory, least recently used evicted first (0 = unlimited)")
+	cacheCompressMin := flag.Int("cache-compress-min", 4096, "gzip cached responses at least this many bytes (0 = off)")
+	staticTTL := flag.Duration("static-ttl", 24*time.Hour, "how long location and pokemon responses stay valid (they almost never change)")
+	serveStale := flag.Duration("serve-stale", 0, "keep serving expired responses this much longer while refreshing them in the background (0 = off)")
+	revalidateWindow := flag.Duration("revalidate-window", 7*24*time.Hour, "keep expired responses with an ETag/Last-Modified on disk this much longer so a refresh can be a cheap 304 (0 = off)")
+	catchFormula := flag.String("catch-formula", catchFormulaCapture, "how catch chances are worked out: capture (the real games' capture rates) or classic (base experience)")
+	recordDir := flag.String("record", "", "record every PokeAPI response into this fixtures directory (uses a memory only cache)")
+	replayDir := flag.String("replay", "", "serve PokeAPI responses only from this fixtures directory (no network, uses a memory only cache)")
+	flag.Parse()
+
+	// record + replay check, replaying what we record makes no sense
+	if *recordDir != "" && *replayDir != "" {
+		fmt.Println("error: -record and -replay can't be used together")
+		os.Exit(2) // same exit code flag uses for bad usage
+	}
+
+	// catch formula check
+	if *catchFormula != catchFormulaCapture && *catchFormula != catchFormulaClassic {
+		fmt.Printf("error: unknown -catch-formula %s (use capture or classic)\n", *catchFormula)
+		os.Exit(2) // same exit code flag uses for bad usage
+	}
+
 	// create cache for performant results
-	cache := pokecache.NewCache(5 * time.Minute) // set cache to 2 minutes
+	cache := newCache(*cacheTTL, responseCacheDir(*cacheDir, *recordDir, *replayDir),
+		pokecache.WithMaxEntries(*cacheMaxEntries),
+		pokecache.WithMaxBytes(*cacheMaxBytes),
+		pokecache.WithCompression(*cacheCompressMin),
+		pokecache.WithStaleWhileRevalidate(*serveStale),
+		pokecache.WithRevalidateWindow(*revalidateWindow),
+	)
 
 	// create the pokeapi client
-	pokeClient := pokeapi.NewClient(cache)
+	opts := []pokeapi.Option{
+		pokeapi.WithBaseURL(*baseURL),
+		pokeapi.WithCacheTTL("location-area", *staticTTL), // static data, cache it for longer
+		pokeapi.WithCacheTTL("pokemon", *staticTTL),
+		pokeapi.WithCacheTTL("pokemon-species", *staticTTL),
+		pokeapi.WithCacheTTL("item", *staticTTL),
+	}
+	if *recordDir != "" {
+		opts = append(opts, pokeapi.WithRecorder(*recordDir)) // save real responses as fixtures
+	}
+	if *replayDir != "" {
+		opts = append(opts, pokeapi.WithReplay(*replayDir)) // offline, fixtures only
+	}
+	pokeClient := pokeapi.NewClient(cache, opts...)
+
+	// create the pokedex and load previous catches from disk
+	pokedex := loadPokedex(*savePath)
 
 	// call start REPL to run the application
-	startREPL(pokeClient) // startrepl will use this for api requests
+	startREPL(&pokeClient, pokedex, cache, *catchFormula) // startrepl will use this for api requests
+
+	// REPL exited, stop the cache reaper and flush the disk cache
+	if err := cache.Close(); err != nil {
+		fmt.Println("warning:", err)
+	}
+}
+
+// defaultCacheDir returns the default on-disk cache dir ($XDG_CACHE_HOME/pokedexcli)
+// empty if the user cache dir can't be determined (memory only cache)
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "" // no cache dir, stay in memory
+	}
+	return filepath.Join(dir, "pokedexcli")
+}
+
+// responseCacheDir picks the on-disk cache dir for this run
+// recording and replaying get a fresh memory only cache (empty dir): a warm disk cache would
+// answer urls before they're recorded, and replay must fail on urls that were never recorded
+func responseCacheDir(cacheDir, recordDir, replayDir string) string {
+	// fixtures check
+	if recordDir != "" || replayDir != "" {
+		return "" // ignore -cache-dir
+	}
+	return cacheDir
+}
+
+// newCache creates the response cache, persisted to dir if one is given
+// falls back to a memory only cache if the dir can't be used
+func newCache(interval time.Duration, dir string, opts ...pokecache.Option) *pokecache.Cache {
+	// memory only check
+	if dir == "" {
+		return pokecache.NewCache(interval, opts...) // early return
+	}
+
+	// disk backed cache
+	cache, err := pokecache.NewPersistentCache(interval, dir, opts...)
+
+	// disk cache check
+	if err != nil {
+		fmt.Printf("warning: %v (using memory only cache)\n", err)
+		return pokecache.NewCache(interval, opts...)
+	}
+	return cache
+}
+
+// loadPokedex creates a pokedex backed by the save file at path
+// a missing save is a fresh start, a corrupt save is reported and moved aside (never silently discarded)
+func loadPokedex(path string) *pokeapi.Pokedex {
+	// init empty pokedex
+	pokedex := pokeapi.NewPokedex()
+
+	// no save path check (saving disabled)
+	if path == "" {
+		return pokedex // early return
+	}
+
+	// load previous session
+	err := pokedex.Load(path)
+
+	// load check
+	switch {
+	case err == nil:
+		// loaded fine
+	case errors.Is(err, fs.ErrNotExist):
+		// first run, nothing to load
+	case errors.Is(err, pokeapi.ErrCorruptSave):
+		// keep the broken file for inspection, autosave would overwrite it otherwise
+		backup := path + ".corrupt-" + time.Now().Format("20060102-150405")
+		fmt.Printf("warning: %v\n", err)
+		if renameErr := os.Rename(path, backup); renameErr != nil {
+			fmt.Printf("warning: could not back up corrupt save, autosave disabled: %v\n", renameErr)
+			return pokedex // don't set save path, never clobber the only copy
+		}
+		fmt.Printf("corrupt save moved to %s, starting with an empty Pokedex\n", backup)
+	default:
+		// unreadable (permissions etc), don't risk overwriting it
+		fmt.Printf("warning: %v (autosave disabled)\n", err)
+		return pokedex
+	}
+
+	// enable autosave
+	pokedex.SetSavePath(path)
+	return pokedex
+}
+
+// serveMock runs the bundled PokeAPI stand-in server until killed
+// point the REPL at it with -base-url http://<addr>/api/v2
+func serveMock(args []string) {
+	// subcommand flags
+	flags := flag.NewFlagSet("serve-mock", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	flags.Parse(args)
+
+	// build the handler from the embedded dataset
+	handler, err := mockapi.NewHandler()
+
+	// handler check
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Mock PokeAPI listening on http://%s/api/v2\n", *addr)
+	fmt.Printf("Run the Pokedex against it with: pokedexcli -base-url http://%s/api/v2\n", *addr)
+
+	// serve until killed
+	if err := http.ListenAndServe(*addr, handler); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,62 @@
+// main_test.go
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/PietPadda/pokedexcli/internal/pokeapi"
+	"github.com/PietPadda/pokedexcli/internal/pokecache"
+)
+
+func TestRecordReplayWarmCacheDir(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "pikachu", "id": 25}`))
+	}))
+	defer server.Close()
+	baseURL := server.URL + "/api/v2"
+
+	// warm the on-disk cache with pikachu and mewtwo
+	cacheDir := t.TempDir()
+	warm, err := pokecache.NewPersistentCache(time.Minute, cacheDir)
+	if err != nil {
+		t.Fatalf("NewPersistentCache unsuccesful: %v", err)
+	}
+	warmClient := pokeapi.NewClient(warm, pokeapi.WithBaseURL(baseURL))
+	for _, name := range []string{"pikachu", "mewtwo"} {
+		if _, err := warmClient.GetPokemonStats(name); err != nil {
+			t.Fatalf("GetPokemonStats(%s) unsuccesful: %v", name, err)
+		}
+	}
+	if err := warm.Close(); err != nil {
+		t.Fatalf("Close unsuccesful: %v", err)
+	}
+
+	// record pikachu, it's in the warm cache but must still be recorded
+	fixtures := t.TempDir()
+	recordCache := newCache(time.Minute, responseCacheDir(cacheDir, fixtures, ""))
+	t.Cleanup(func() { recordCache.Close() })
+	recorder := pokeapi.NewClient(recordCache, pokeapi.WithBaseURL(baseURL), pokeapi.WithRecorder(fixtures))
+	if _, err := recorder.GetPokemonStats("pikachu"); err != nil {
+		t.Fatalf("GetPokemonStats unsuccesful: %v", err)
+	}
+	if entries, _ := os.ReadDir(fixtures); len(entries) != 1 {
+		t.Errorf("expected pikachu to be recorded, got %d fixtures", len(entries))
+	}
+	server.Close() // no network from here on
+
+	// replay: pikachu was recorded, mewtwo wasn't (even though the disk cache has it)
+	replayCache := newCache(time.Minute, responseCacheDir(cacheDir, "", fixtures))
+	t.Cleanup(func() { replayCache.Close() })
+	replayer := pokeapi.NewClient(replayCache, pokeapi.WithBaseURL(baseURL), pokeapi.WithReplay(fixtures))
+	if _, err := replayer.GetPokemonStats("pikachu"); err != nil {
+		t.Errorf("expected pikachu to replay, got %v", err)
+	}
+	if _, err := replayer.GetPokemonStats("mewtwo"); !errors.Is(err, pokeapi.ErrNoFixture) {
+		t.Errorf("expected ErrNoFixture for an unrecorded url, got %v", err)
+	}
+}
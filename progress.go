@@ -0,0 +1,32 @@
+// progress.go
+// for drawing progress bars in the terminal during long commands
+package main
+
+import (
+	"fmt"     // for printing
+	"strings" // for building the bar
+)
+
+// width of the bar itself, in characters
+const progressBarWidth = 30
+
+// printProgress redraws a progress bar in place, eg "[#######-------] 42/151"
+// prints a newline once done == total so the next output starts on its own line
+func printProgress(done, total int) {
+	// nothing to show check (also avoids dividing by zero)
+	if total <= 0 {
+		return
+	}
+
+	// how much of the bar is filled
+	filled := progressBarWidth * done / total
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", progressBarWidth-filled)
+
+	// \r = back to the start of the line, so the bar redraws over itself
+	fmt.Printf("\r[%s] %d/%d", bar, done, total)
+
+	// finished check
+	if done == total {
+		fmt.Println()
+	}
+}
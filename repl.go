@@ -4,21 +4,53 @@ package main // all files in same folder form part of package main
 import (
 	// import standard libraries
 	"bufio"     // for input blocking
+	"context"   // for cancelling in-flight commands
+	"errors"    // for checking cancelled commands
 	"fmt"       // for printing
 	"math/rand" // for catch probability
 	"os"        // for OS input
+	"os/signal" // for Ctrl+C handling
+	"sort"      // for listing inventory items
+	"strconv"   // for parsing prefetch ranges
 	"strings"   // for Fields (split whitespace) and ToLower (lowercase)
+	"time"      // for cache entry ages
 
 	// import internal packages
-	"github.com/PietPadda/pokedexcli/internal/pokeapi" // our internal package pokeapi
+	"github.com/PietPadda/pokedexcli/internal/pokeapi"   // our internal package pokeapi
+	"github.com/PietPadda/pokedexcli/internal/pokecache" // our internal package pokecache
 )
 
 // for paginating through location areas
 type config struct {
-	NextURL       string           // next 20 areas (map command)
-	PrevURL       string           // previous 20 areas (mapb command)
-	PokeapiClient pokeapi.Client   // client to make API calls
-	Pokedex       *pokeapi.Pokedex // for storing caught pokemon
+	NextURL       string                       // next 20 areas (map command)
+	PrevURL       string                       // previous 20 areas (mapb command)
+	PokeapiClient pokeapi.API                  // client to make API calls (interface so tests can use a fake)
+	Pokedex       *pokeapi.Pokedex             // for storing caught pokemon
+	Cache         *pokecache.Cache             // response cache (same one the client uses), for the cache command
+	Rand          *rand.Rand                   // random source for catching (nil = global, tests seed their own)
+	Ctx           context.Context              // current command's context, cancelled by Ctrl+C (nil = background)
+	CatchFormula  string                       // catchFormulaCapture (default, also when empty) or catchFormulaClassic
+	Inventory     *pokeapi.Inventory           // pokeballs etc, lives in (and is saved with) the Pokedex
+	Location      *pokeapi.LocationAreaDetails // area we last explored, catch only finds pokemon living here (nil = nowhere yet)
+	Encounter     *wildEncounter               // pokemon walk/fish/surf turned up, a bare catch throws at it (nil = none)
+}
+
+// requestContext returns the context api calls should use for the current command
+func (cfg *config) requestContext() context.Context {
+	// nil check (tests etc don't set one)
+	if cfg.Ctx == nil {
+		return context.Background()
+	}
+	return cfg.Ctx
+}
+
+// intn returns a random int in [0, n) from the config's random source
+func (cfg *config) intn(n int) int {
+	// nil check, use the global source
+	if cfg.Rand == nil {
+		return rand.Intn(n)
+	}
+	return cfg.Rand.Intn(n)
 }
 
 // our command registry (abstraction)
@@ -27,10 +59,14 @@ type cliCommand struct {
 	name        string
 	description string
 	callback    func(*config, []string) error
+	keepCase    bool // args are free text (nicknames, paths), pass them as typed instead of lowercased
 	// callback *config pointer for pagination & pokeapi client
 	// callback []string for command handling of parameters
 }
 
+// errExit is returned by the exit command to stop the REPL loop
+var errExit = errors.New("exit")
+
 // CORE: we pass config ptr to callback to allow NEXT & PREVIOUS pagination to all commands
 
 // returns a map of all REPL commands we have
@@ -64,9 +100,24 @@ func getCommands() map[string]cliCommand {
 		},
 		"catch": { // catch command -- attempt to catch pokemon at location
 			name:        "catch",
-			description: "Try to catch Pokemon (takes pokemon arg)",
+			description: "Try to catch Pokemon in the last explored area (takes pokemon arg, or none to catch what walk/fish/surf found, optional --ball <poke, great, ultra, master>, --hp <percent> and --status <sleep, paralysis...>)",
 			callback:    commandCatch,
 		},
+		"walk": { // walk command -- wander the grass for a random wild pokemon
+			name:        "walk",
+			description: "Walk around the last explored area until a wild Pokemon appears",
+			callback:    commandWalk,
+		},
+		"fish": { // fish command -- fish for a random wild pokemon
+			name:        "fish",
+			description: "Fish in the last explored area (optional rod arg <old, good, super>, any rod by default)",
+			callback:    commandFish,
+		},
+		"surf": { // surf command -- surf for a random wild pokemon
+			name:        "surf",
+			description: "Surf around the last explored area until a wild Pokemon appears",
+			callback:    commandSurf,
+		},
 		"inspect": { // inspect command -- attempt to list stats of a pokemon in the pokedex (if caught)
 			name:        "inspect",
 			description: "Lists stats of pokemon in pokedex (takes pokemon arg)",
@@ -77,6 +128,50 @@ func getCommands() map[string]cliCommand {
 			description: "Lists all pokemon caught in the pokedex",
 			callback:    commandPokedex,
 		},
+		"save": { // save command -- writes the pokedex to disk
+			name:        "save",
+			description: "Save the pokedex to disk (takes optional path arg)",
+			callback:    commandSave,
+			keepCase:    true, // paths are case sensitive
+		},
+		"cache": { // cache command -- inspect and manage the response cache
+			name:        "cache",
+			description: "Manage the response cache (takes stats, list, clear or evict <url>)",
+			callback:    commandCache,
+			keepCase:    true, // cache keys (urls) are case sensitive
+		},
+		"load": { // load command -- replaces the pokedex with a save from disk
+			name:        "load",
+			description: "Load the pokedex from disk (takes optional path arg)",
+			callback:    commandLoad,
+			keepCase:    true, // paths are case sensitive
+		},
+		"inventory": { // inventory command -- lists the items in the bag
+			name:        "inventory",
+			description: "List the items in your bag (takes optional item arg for details)",
+			callback:    commandInventory,
+		},
+		"box": { // box command -- lists every individual pokemon caught
+			name:        "box",
+			description: "List the Pokemon you've caught (takes optional box id arg for ivs, nature and where it was caught)",
+			callback:    commandBox,
+		},
+		"nickname": { // nickname command -- names a caught pokemon
+			name:        "nickname",
+			description: "Nickname a caught Pokemon (takes box id and nickname args, no nickname clears it)",
+			callback:    commandNickname,
+			keepCase:    true, // "Sparky" stays "Sparky"
+		},
+		"release": { // release command -- lets a caught pokemon go
+			name:        "release",
+			description: "Release a caught Pokemon (takes box id arg, it stays in your Pokedex)",
+			callback:    commandRelease,
+		},
+		"prefetch": { // prefetch command -- warms the cache so the session can run offline
+			name:        "prefetch",
+			description: "Download data ahead of time (takes locations, or pokemon <from-to>, eg pokemon 1-151)",
+			callback:    commandPrefetch,
+		},
 	}
 }
 
@@ -85,8 +180,7 @@ func getCommands() map[string]cliCommand {
 // accepts args for command parameters
 func commandExit(cfg *config, args []string) error {
 	fmt.Println("Closing the Pokedex... Goodbye!")
-	os.Exit(0) // neatly terminate program
-	return nil // no error on exit
+	return errExit // startREPL returns, so main can close the cache neatly
 }
 
 // callback - lists all registered commands
@@ -114,16 +208,11 @@ func commandHelp(cfg *config, args []string) error {
 // accepts config file for pagination & pokeapi client
 // accepts args for command parameters
 func commandMap(cfg *config, args []string) error {
-	// first set the default url if no request has been made
-	url := cfg.NextURL
-
-	// no request made check
-	if url == "" {
-		url = "https://pokeapi.co/api/v2/location-area" // default starting url
-	}
+	// get the page url, empty if no request has been made yet
+	url := cfg.NextURL // empty = client starts at its own base url + /location-area
 
 	// next we make API request using the pokeapi client
-	res, err := cfg.PokeapiClient.GetLocationAreas(url) // pass the url here
+	res, err := cfg.PokeapiClient.GetLocationAreasContext(cfg.requestContext(), url) // pass the url here
 
 	// server response check
 	if err != nil {
@@ -160,16 +249,11 @@ func commandMap(cfg *config, args []string) error {
 // accepts config file for pagination & pokeapi client
 // accepts args for command parameters
 func commandMapb(cfg *config, args []string) error {
-	// first set the default url if no request has been made
-	url := cfg.PrevURL
-
-	// no request made check
-	if url == "" {
-		url = "https://pokeapi.co/api/v2/location-area" // default starting url
-	}
+	// get the page url, empty if no request has been made yet
+	url := cfg.PrevURL // empty = client starts at its own base url + /location-area
 
 	// next we make API request using the pokeapi client
-	res, err := cfg.PokeapiClient.GetLocationAreas(url) // pass the url here
+	res, err := cfg.PokeapiClient.GetLocationAreasContext(cfg.requestContext(), url) // pass the url here
 
 	// server response check
 	if err != nil {
@@ -220,14 +304,23 @@ func commandExplore(cfg *config, args []string) error {
 	locationAreaName := args[0] // location area is first arg
 
 	// use pokeapi client to fetch the pokemon from this location
-	res, err := cfg.PokeapiClient.GetLocationArea(locationAreaName) // pass location name here
+	res, err := cfg.PokeapiClient.GetLocationAreaContext(cfg.requestContext(), locationAreaName) // pass location name here
 	// REVIEW: config holds client field, client fetches data with method called on it, method uses location area
 
+	// not found check (typo'd location)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return fmt.Errorf("no location area named %s (use map to list them)", locationAreaName)
+	}
+
 	// fetch check
 	if err != nil {
 		return fmt.Errorf("error client fetching pokemon from location: %w", err)
 	}
 
+	// we're here now, catch only finds what lives in this area
+	cfg.Location = &res
+	cfg.Encounter = nil // anything we met before is left behind
+
 	// loop thru response results and print all pokemon to terminal
 	fmt.Printf("Exploring %s...\n", locationAreaName) // initial print before looping
 
@@ -239,8 +332,14 @@ func commandExplore(cfg *config, args []string) error {
 
 	fmt.Println("Found Pokemon:")                     // initial print before looping
 	for _, encounter := range res.PokemonEncounters { // from PokemonEncounters (PE) in client.go
-		// print each pokemon with a newline
-		fmt.Printf("- %s\n", encounter.Pokemon.Name) // from PokemonEncounters
+		// no encounter data check (always around)
+		if len(encounter.VersionDetails) == 0 {
+			fmt.Printf("- %s\n", encounter.Pokemon.Name)
+			continue
+		}
+
+		// print each pokemon and how likely we are to find it, with a newline
+		fmt.Printf("- %s (%d%%)\n", encounter.Pokemon.Name, encounter.Chance()) // from PokemonEncounters
 	}
 
 	// return success
@@ -256,50 +355,118 @@ func commandCatch(cfg *config, args []string) error {
 		return fmt.Errorf("error: config is nil") // early return custom error
 	}
 
+	// wild encounter check, catch with no name throws at it
+	wildName := ""
+	if cfg.Encounter != nil {
+		wildName = cfg.Encounter.name
+	}
+
+	// get pokemon name and --hp/--status options from args
+	pokemonName, opts, err := parseCatchArgs(args, wildName)
+
 	// args check
-	if len(args) == 0 { // no arg(s) provided
-		return fmt.Errorf("error: catch must take pokemon name as argument") // early return custom error
+	if err != nil {
+		return err // early return, already descriptive
 	}
 
-	// get location area name from args
-	pokemonName := args[0] // pokemon name is first arg
+	// location check, pokemon have to live where we are
+	encounter, err := findEncounter(cfg, pokemonName)
+	if err != nil {
+		return err // early return, already descriptive
+	}
+	wild := cfg.Encounter != nil && pokemonName == cfg.Encounter.name // already right in front of us
 
 	// use pokeapi client to fetch the pokemon details
-	res, err := cfg.PokeapiClient.GetPokemonStats(pokemonName) // pass pokemon name here
+	res, err := cfg.PokeapiClient.GetPokemonStatsContext(cfg.requestContext(), pokemonName) // pass pokemon name here
 	// REVIEW: config holds client field, client fetches data with method called on it, method uses location area
 
+	// not found check (typo'd name), suggest close matches
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return notFoundPokemonError(cfg, pokemonName)
+	}
+
 	// fetch check
 	if err != nil {
 		return fmt.Errorf("error client fetching pokemon details: %w", err)
 	}
 
-	// get pokemon base experience for catch probability calc
-	baseExperience := res.BaseExperience // from PokemonStats struct
+	// ball check, need one in the bag to throw it
+	ball := catchBalls[opts.ball]
+	if cfg.Inventory.Count(ball.item) == 0 {
+		return fmt.Errorf("you have no %ss left, thrown balls don't come back (see inventory)", ball.label)
+	}
 
-	// calculate probability of catching using inverse proportion for simplicity
-	catchRate := 100.0 / (1.0 + float64(baseExperience)/60.0) // roll to beat, decreases with more base xp
-	// 60xp = 50%
-	// 180xp = 25%
-	// 1140xp = 5%
+	// encounter check, rarer pokemon take some finding (no ball thrown if it doesn't show up)
+	if !wild && len(encounter.VersionDetails) > 0 && cfg.intn(100) >= encounter.Chance() {
+		fmt.Printf("You searched %s but couldn't find a %s.\n", cfg.Location.Name, pokemonName)
+		return nil
+	}
 
-	// determine catch success
-	catchRoll := rand.Intn(96)                 // random roll from 0 to 95 (last int not incl)
-	catchSuccess := catchRoll < int(catchRate) // if we roll less than catch rate, this is true ie caught
+	// it's in front of us, so it's been seen (caught or not)
+	if err := cfg.Pokedex.PokemonSeen(pokemonName); err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
 
-	// initial print before determining success or failure of ctaching
-	fmt.Printf("Throwing a Pokeball at %s...\n", pokemonName)
+	// determine catch success with the configured formula
+	var catchSuccess bool
+	if cfg.CatchFormula == catchFormulaClassic {
+		// initial print before determining success or failure of ctaching
+		throwBall(cfg, ball, pokemonName)
+
+		catchSuccess = classicRoll(cfg, res.BaseExperience, ball.bonus) // from PokemonStats struct
+	} else {
+		// species has the real capture rate
+		species, err := cfg.PokeapiClient.GetPokemonSpeciesContext(cfg.requestContext(), res.SpeciesName())
+
+		// fetch check
+		if err != nil {
+			return fmt.Errorf("error client fetching pokemon species: %w", err)
+		}
+
+		// legendary check, just so the player knows what they're up against
+		if species.IsLegendary || species.IsMythical {
+			fmt.Printf("%s is a legendary Pokemon, good luck!\n", pokemonName)
+		}
+
+		// initial print before determining success or failure of ctaching
+		throwBall(cfg, ball, pokemonName)
+
+		var shakes int
+		catchSuccess, shakes = captureRoll(cfg, species.CaptureRate, ball.bonus, opts)
+		if !catchSuccess && shakes > 0 {
+			fmt.Printf("The ball shook %d time(s)...\n", shakes)
+		}
+	}
+
+	// wild encounter is over either way (caught or ran off), its level is all we still need
+	level := 0
+	if wild {
+		level = cfg.Encounter.level
+		cfg.Encounter = nil
+	}
 
 	// catch success check
 	if catchSuccess { // true
 		fmt.Printf("%s was caught!\n", pokemonName) // caught a pokemon
 
-		// add to pokedex here -- to be implemented later
-		cfg.Pokedex.PokemonAdd(pokemonName, res)
-		// we use the method PokemonAdd on the pokedex to add a pokemon to it
+		// caught by name, roll the level it would have turned up at
+		if level == 0 {
+			level = encounterLevel(cfg, cfg.Location.Name, encounter)
+		}
+
+		// add to pokedex (species) and box (this one)
+		caught, err := cfg.Pokedex.Catch(res, newCaughtPokemon(cfg, pokemonName, level, cfg.Location.Name))
+		// we use the method Catch on the pokedex to add a pokemon to it
 		// Pokedex is init in config and thus a field of cfg
 
+		// add check (autosave failure still adds the pokemon in memory)
+		if err != nil {
+			fmt.Printf("warning: %v\n", err)
+		}
+
 		// NOTE: res = PokemonStats!
 		fmt.Printf("%s has been added to the Pokedex!\n", pokemonName) // indicate added to pokedex
+		fmt.Printf("Sent to your box as #%d (Lv. %d, %s nature).\n", caught.ID, caught.Level, caught.Nature)
 
 	} else { // false
 		fmt.Printf("%s escaped!\n", pokemonName) // it escaped
@@ -310,6 +477,275 @@ func commandCatch(cfg *config, args []string) error {
 	return nil
 }
 
+// commandWalk rolls a wild encounter in the grass of the last explored area
+func commandWalk(cfg *config, args []string) error {
+	return wander(cfg, "walk", walkMethods, args)
+}
+
+// commandFish rolls a wild encounter on the end of a rod (any rod, or the one given)
+func commandFish(cfg *config, args []string) error {
+	// nil ptr check (Go Best Practice)
+	if cfg == nil {
+		return fmt.Errorf("error: config is nil") // early return custom error
+	}
+
+	// get rod(s) from args
+	methods, err := fishMethods(args)
+
+	// args check
+	if err != nil {
+		return err // early return, already descriptive
+	}
+	return wander(cfg, "fish", methods, nil)
+}
+
+// commandSurf rolls a wild encounter on the water of the last explored area
+func commandSurf(cfg *config, args []string) error {
+	return wander(cfg, "surf", surfMethods, args)
+}
+
+// wander rolls a wild encounter met by one of methods, which becomes catch's target
+// command is just for messages (walk, fish or surf)
+func wander(cfg *config, command string, methods []string, args []string) error {
+	// nil ptr check (Go Best Practice)
+	if cfg == nil {
+		return fmt.Errorf("error: config is nil") // early return custom error
+	}
+
+	// args check
+	if len(args) > 0 {
+		return fmt.Errorf("error: %s takes no arguments", command)
+	}
+
+	// nowhere yet check
+	if cfg.Location == nil {
+		return fmt.Errorf("you haven't explored anywhere yet (explore an area first)")
+	}
+
+	// roll the encounter
+	encounter, ok := rollEncounter(cfg, cfg.Location, methods)
+
+	// nothing here check
+	if !ok {
+		fmt.Printf("No Pokemon at %s can be met by %s.\n", cfg.Location.Name, strings.Join(methods, ", "))
+		return nil
+	}
+
+	// it's now catch's target, and seen
+	cfg.Encounter = &encounter
+	if err := cfg.Pokedex.PokemonSeen(encounter.name); err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+	fmt.Printf("A wild %s (Lv. %d) appeared!\n", encounter.name, encounter.level)
+	fmt.Printf("Use catch to throw a ball at it.\n")
+	return nil
+}
+
+// findEncounter finds pokemonName among the pokemon living in the last explored area
+// errors (with "did you mean" suggestions from the area) if it doesn't live there
+func findEncounter(cfg *config, pokemonName string) (pokeapi.PokemonEncounter, error) {
+	// nowhere yet check
+	if cfg.Location == nil {
+		return pokeapi.PokemonEncounter{}, fmt.Errorf("you haven't explored anywhere yet (explore an area to find Pokemon to catch)")
+	}
+
+	// look thru the area's pokemon
+	names := make([]string, 0, len(cfg.Location.PokemonEncounters))
+	for _, encounter := range cfg.Location.PokemonEncounters {
+		if encounter.Pokemon.Name == pokemonName {
+			return encounter, nil
+		}
+		names = append(names, encounter.Pokemon.Name)
+	}
+
+	// suggestions check, maybe it's a typo of one that does live here
+	if suggestions := suggestNames(pokemonName, names); len(suggestions) > 0 {
+		return pokeapi.PokemonEncounter{}, fmt.Errorf("no %s at %s \u2014 did you mean %s?", pokemonName, cfg.Location.Name, strings.Join(suggestions, ", "))
+	}
+
+	// not a pokemon at all check (a typo of one that lives elsewhere), suggest from every pokemon
+	if !knownPokemon(cfg, pokemonName) {
+		return pokeapi.PokemonEncounter{}, notFoundPokemonError(cfg, pokemonName)
+	}
+	return pokeapi.PokemonEncounter{}, fmt.Errorf("no %s at %s (explore lists the Pokemon living here)", pokemonName, cfg.Location.Name)
+}
+
+// throwBall takes the ball out of the bag and announces the throw
+func throwBall(cfg *config, ball pokeball, pokemonName string) {
+	// use check (only an autosave can fail here, we checked the count already)
+	if err := cfg.Inventory.Use(ball.item); err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+	fmt.Printf("Throwing %s at %s...\n", ball.label, pokemonName)
+}
+
+// commandBox lists every pokemon in the box, or one in detail
+func commandBox(cfg *config, args []string) error {
+	// nil ptr check (Go Best Practice)
+	if cfg == nil {
+		return fmt.Errorf("error: config is nil") // early return custom error
+	}
+
+	// one pokemon check
+	if len(args) > 0 {
+		id, err := parseBoxID("box", args[0])
+		if err != nil {
+			return err // early return, already descriptive
+		}
+
+		caught, ok, err := cfg.Pokedex.BoxGet(id)
+
+		// get check
+		if err != nil {
+			return fmt.Errorf("error getting pokemon from box: %w", err)
+		}
+
+		// in box check
+		if !ok {
+			return fmt.Errorf("no #%d in your box (see box)", id)
+		}
+		printBoxDetails(caught)
+		return nil
+	}
+
+	// the whole box
+	box, err := cfg.Pokedex.Box()
+
+	// get check
+	if err != nil {
+		return fmt.Errorf("error getting box: %w", err)
+	}
+
+	// empty check
+	if len(box) == 0 {
+		fmt.Println("Your box is empty.")
+		return nil
+	}
+
+	fmt.Println("Your box:")
+	for _, caught := range box {
+		fmt.Printf(" - %s\n", boxLine(caught))
+	}
+	return nil
+}
+
+// commandNickname gives a caught pokemon a nickname, or clears it
+func commandNickname(cfg *config, args []string) error {
+	// nil ptr check (Go Best Practice)
+	if cfg == nil {
+		return fmt.Errorf("error: config is nil") // early return custom error
+	}
+
+	// args check
+	if len(args) == 0 {
+		return fmt.Errorf("error: nickname must take a box id (and a nickname) as arguments")
+	}
+
+	id, err := parseBoxID("nickname", args[0])
+	if err != nil {
+		return err // early return, already descriptive
+	}
+
+	// rename it, everything after the id is the nickname
+	caught, err := cfg.Pokedex.Nickname(id, strings.Join(args[1:], " "))
+
+	// in box check
+	if errors.Is(err, pokeapi.ErrNotInBox) {
+		return fmt.Errorf("no #%d in your box (see box)", id)
+	}
+
+	// nickname check (autosave failure still renames it in memory)
+	if err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+
+	// cleared check
+	if caught.Nickname == "" {
+		fmt.Printf("#%d is just %s again.\n", caught.ID, caught.Species)
+		return nil
+	}
+	fmt.Printf("#%d %s is now called %s.\n", caught.ID, caught.Species, caught.Nickname)
+	return nil
+}
+
+// commandRelease lets a caught pokemon go, the species stays in the Pokedex
+func commandRelease(cfg *config, args []string) error {
+	// nil ptr check (Go Best Practice)
+	if cfg == nil {
+		return fmt.Errorf("error: config is nil") // early return custom error
+	}
+
+	// args check
+	if len(args) != 1 {
+		return fmt.Errorf("error: release must take a box id as argument")
+	}
+
+	id, err := parseBoxID("release", args[0])
+	if err != nil {
+		return err // early return, already descriptive
+	}
+
+	caught, err := cfg.Pokedex.Release(id)
+
+	// in box check
+	if errors.Is(err, pokeapi.ErrNotInBox) {
+		return fmt.Errorf("no #%d in your box (see box)", id)
+	}
+
+	// release check (autosave failure still releases it in memory)
+	if err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+
+	fmt.Printf("Bye bye, %s!\n", caught.DisplayName())
+	return nil
+}
+
+// knownPokemon reports whether pokemonName is a real pokemon (from the cached pokemon list)
+// true if the list can't be fetched, we can't tell so we don't claim it's a typo
+func knownPokemon(cfg *config, pokemonName string) bool {
+	// get every pokemon name (cached after the first time)
+	list, err := cfg.PokeapiClient.GetPokemonListContext(cfg.requestContext())
+
+	// list check
+	if err != nil {
+		return true
+	}
+
+	// look thru the names
+	for _, pokemon := range list.Results {
+		if pokemon.Name == pokemonName {
+			return true
+		}
+	}
+	return false
+}
+
+// notFoundPokemonError builds a friendly "no Pokemon named X" error with "did you mean" suggestions
+// suggestions are best effort, if the name list can't be fetched we just skip them
+func notFoundPokemonError(cfg *config, pokemonName string) error {
+	// get every pokemon name (cached after the first time)
+	list, err := cfg.PokeapiClient.GetPokemonListContext(cfg.requestContext())
+
+	// list check, no suggestions
+	if err != nil {
+		return fmt.Errorf("no Pokemon named %s", pokemonName)
+	}
+
+	// collect names for matching
+	names := make([]string, 0, len(list.Results))
+	for _, pokemon := range list.Results {
+		names = append(names, pokemon.Name)
+	}
+
+	// suggestions check
+	suggestions := suggestNames(pokemonName, names)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("no Pokemon named %s", pokemonName)
+	}
+	return fmt.Errorf("no Pokemon named %s \u2014 did you mean %s?", pokemonName, strings.Join(suggestions, ", "))
+}
+
 // callback - prints pokemon stats that's caught in pokedex
 // accepts config file for pokedex
 // accepts args for command parameters
@@ -391,31 +827,380 @@ func commandPokedex(cfg *config, args []string) error {
 		return fmt.Errorf("error getting all pokemon from pokedex: %w", err) // early return
 	}
 
+	// get every species seen (caught ones too)
+	seen, err := cfg.Pokedex.PokemonGetAllSeen()
+
+	// get seen check
+	if err != nil {
+		return fmt.Errorf("error getting seen pokemon from pokedex: %w", err) // early return
+	}
+
 	// empty amp check
-	if len(names) == 0 {
+	if len(names) == 0 && len(seen) == 0 {
 		fmt.Println("You have not caught any pokemon yet!")
 		return nil //early return
 	}
 
 	// there are pokemon! let's proceed with print
+	fmt.Printf("Seen: %d, Caught: %d\n", len(seen), len(names))
+
+	// caught lookup, the rest were only seen
+	caught := make(map[string]bool, len(names))
+	for _, pokemonName := range names {
+		caught[pokemonName] = true
+	}
+
+	// loop thru pokedex to get names (sorted, maps have no order)
+	sort.Strings(seen)
+	for _, pokemonName := range seen { // names of pokemon in pokedex
+		if caught[pokemonName] {
+			fmt.Printf(" - %s\n", pokemonName) // print pokedex pokemon
+		} else {
+			fmt.Printf(" - %s (seen)\n", pokemonName)
+		}
+	}
+
+	// return success
+	return nil
+}
+
+// callback - saves the pokedex to disk
+// accepts config file for pokedex
+// accepts optional path arg, defaults to the configured save path
+func commandSave(cfg *config, args []string) error {
+	// nil ptr check (Go Best Practice)
+	if cfg == nil {
+		return fmt.Errorf("error: config is nil") // early return custom error
+	}
+
+	// get optional path from args
+	path := "" // empty = configured save path
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	// save pokedex
+	err := cfg.Pokedex.Save(path)
+
+	// save check
+	if err != nil {
+		return fmt.Errorf("error saving pokedex: %w", err)
+	}
+
+	// display where we saved
+	if path == "" {
+		path = cfg.Pokedex.SavePath()
+	}
+	fmt.Printf("Pokedex saved to %s\n", path)
+
+	// return success
+	return nil
+}
+
+// callback - loads the pokedex from disk, replacing the current one
+// accepts config file for pokedex
+// accepts optional path arg, defaults to the configured save path
+func commandLoad(cfg *config, args []string) error {
+	// nil ptr check (Go Best Practice)
+	if cfg == nil {
+		return fmt.Errorf("error: config is nil") // early return custom error
+	}
+
+	// get optional path from args
+	path := "" // empty = configured save path
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	// load pokedex (corrupt saves are reported, current pokedex is left untouched)
+	err := cfg.Pokedex.Load(path)
+
+	// load check
+	if err != nil {
+		return fmt.Errorf("error loading pokedex: %w", err)
+	}
+
+	// display where we loaded from
+	if path == "" {
+		path = cfg.Pokedex.SavePath()
+	}
+	fmt.Printf("Pokedex loaded from %s\n", path)
+
+	// return success
+	return nil
+}
+
+// callback - shows cache stats, lists, clears or evicts cached responses
+// accepts config file for cache
+// accepts args for subcommand (stats, list, clear, evict <url>)
+func commandCache(cfg *config, args []string) error {
+	// nil ptr check (Go Best Practice)
+	if cfg == nil {
+		return fmt.Errorf("error: config is nil") // early return custom error
+	}
+
+	// args check
+	if len(args) == 0 { // no subcommand provided
+		return fmt.Errorf("error: cache must take stats, list, clear or evict <url> as argument") // early return custom error
+	}
+
+	// run the subcommand (any case, only the url keeps it)
+	switch strings.ToLower(args[0]) {
+	case "stats":
+		stats := cfg.Cache.Stats()
+
+		// hit rate, avoid dividing by zero before any lookups
+		lookups := stats.Hits + stats.StaleHits + stats.Misses
+		hitRate := 0.0
+		if lookups > 0 {
+			hitRate = 100 * float64(stats.Hits+stats.StaleHits) / float64(lookups)
+		}
+
+		// compression ratio, 1x if nothing is compressed
+		ratio := 1.0
+		if stats.Bytes > 0 {
+			ratio = float64(stats.RawBytes) / float64(stats.Bytes)
+		}
+
+		fmt.Println("Cache stats:")
+		fmt.Printf("  Entries: %d\n", stats.Entries)
+		fmt.Printf("  Size: %d bytes (%d uncompressed)\n", stats.Bytes, stats.RawBytes)
+		fmt.Printf("  Compressed: %d entries, %.1fx ratio\n", stats.Compressed, ratio)
+		fmt.Printf("  Hits: %d\n", stats.Hits)
+		fmt.Printf("  Stale hits: %d\n", stats.StaleHits)
+		fmt.Printf("  Misses: %d\n", stats.Misses)
+		fmt.Printf("  Hit rate: %.1f%%\n", hitRate)
+		fmt.Printf("  Evictions: %d\n", stats.Evictions)
+		fmt.Printf("  Expired: %d\n", stats.Expired)
+		fmt.Printf("  Renewed (304): %d\n", stats.Renewals)
+		fmt.Printf("  Decoded hits: %d\n", stats.DecodedHits)
+
+	case "list":
+		entries, err := cfg.Cache.Entries()
+
+		// entries check
+		if err != nil {
+			return fmt.Errorf("error listing cache entries: %w", err)
+		}
 
-	// loop thru pokedex to get names
-	for _, pokemonName := range names { // names of pokemon in pokedex
-		fmt.Printf(" - %s\n", pokemonName) // print pokedex pokemon
+		// empty cache check
+		if len(entries) == 0 {
+			fmt.Println("The cache is empty.")
+			return nil
+		}
+
+		fmt.Println("Cached entries:")
+		for _, entry := range entries {
+			age := time.Since(entry.CreatedAt).Round(time.Second)
+			fmt.Printf(" - %s (%d bytes, %s old)\n", entry.Key, entry.Size, age)
+		}
+
+	case "clear":
+		// clear check
+		if err := cfg.Cache.Clear(); err != nil {
+			return fmt.Errorf("error clearing cache: %w", err)
+		}
+		fmt.Println("Cache cleared.")
+
+	case "evict":
+		// url arg check
+		if len(args) < 2 {
+			return fmt.Errorf("error: cache evict must take url as argument") // early return custom error
+		}
+
+		// evict check
+		found, err := cfg.Cache.Evict(args[1])
+		if err != nil {
+			return fmt.Errorf("error evicting cache entry: %w", err)
+		}
+		if !found {
+			fmt.Printf("%s was not cached.\n", args[1])
+			return nil
+		}
+		fmt.Printf("Evicted %s\n", args[1])
+
+	default:
+		return fmt.Errorf("error: unknown cache subcommand %s (use stats, list, clear or evict <url>)", args[0])
+	}
+
+	// return success
+	return nil
+}
+
+// callback - lists the items in the bag, or one item's details
+// accepts config file for inventory & pokeapi client (item names and descriptions)
+// accepts args for an optional item name
+func commandInventory(cfg *config, args []string) error {
+	// nil ptr check (Go Best Practice)
+	if cfg == nil {
+		return fmt.Errorf("error: config is nil") // early return custom error
+	}
+
+	items := cfg.Inventory.Items() // copy of the bag
+
+	// single item check
+	if len(args) > 0 {
+		name := args[0]
+		if short, ok := catchBalls[strings.TrimSuffix(name, "-ball")]; ok {
+			name = short.item // "great" works as well as "great-ball"
+		}
+
+		// metadata from PokeAPI (cached after the first time)
+		item, err := cfg.PokeapiClient.GetItemContext(cfg.requestContext(), name)
+
+		// not found check
+		if errors.Is(err, pokeapi.ErrNotFound) {
+			return fmt.Errorf("no item named %s", name)
+		}
+
+		// fetch check
+		if err != nil {
+			return fmt.Errorf("error client fetching item: %w", err)
+		}
+
+		fmt.Printf("%s x%d\n", item.DisplayName(), items[item.Name])
+		if description := item.Description(); description != "" {
+			fmt.Println(description)
+		}
+		fmt.Printf("Cost: %d\n", item.Cost)
+		if item.Sprites.Default != "" {
+			fmt.Printf("Sprite: %s\n", item.Sprites.Default)
+		}
+		return nil
+	}
+
+	// empty bag check
+	if len(items) == 0 {
+		fmt.Println("Your bag is empty, thrown balls don't come back.")
+		return nil
+	}
+
+	// balls first (worst to best), then anything else alphabetically
+	names := make([]string, 0, len(items))
+	for _, short := range ballOrder {
+		if items[catchBalls[short].item] > 0 {
+			names = append(names, catchBalls[short].item)
+		}
+	}
+	var others []string
+	for name := range items {
+		if _, isBall := catchBalls[strings.TrimSuffix(name, "-ball")]; !isBall {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	names = append(names, others...)
+
+	fmt.Println("Your bag:")
+	for _, name := range names {
+		// metadata is best effort, the bag is still worth listing offline
+		item, err := cfg.PokeapiClient.GetItemContext(cfg.requestContext(), name)
+		if err != nil {
+			fmt.Printf(" - %s x%d\n", name, items[name])
+			continue
+		}
+		fmt.Printf(" - %s x%d: %s\n", item.DisplayName(), items[name], item.Description())
 	}
 
 	// return success
 	return nil
 }
 
+// callback - fetches every location area, or a range of pokemon, into the cache
+// accepts config file for pokeapi client
+// accepts args for what to fetch (locations, pokemon <from-to>)
+func commandPrefetch(cfg *config, args []string) error {
+	// nil ptr check (Go Best Practice)
+	if cfg == nil {
+		return fmt.Errorf("error: config is nil") // early return custom error
+	}
+
+	// args check
+	if len(args) == 0 { // nothing to fetch
+		return fmt.Errorf("error: prefetch must take locations or pokemon <from-to> as argument") // early return custom error
+	}
+
+	// run the subcommand, every resource fetched goes thru the client's cache
+	var (
+		result pokeapi.PrefetchResult
+		err    error
+		what   string // for the summary
+	)
+	switch args[0] {
+	case "locations":
+		fmt.Println("Prefetching location areas...")
+		result, err = pokeapi.PrefetchLocationAreas(cfg.requestContext(), cfg.PokeapiClient, pokeapi.DefaultPrefetchWorkers, printProgress)
+		what = "location areas"
+
+	case "pokemon":
+		// range arg, the original 151 by default
+		from, to := 1, 151
+		if len(args) > 1 {
+			from, to, err = parseIDRange(args[1])
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("Prefetching pokemon %d-%d...\n", from, to)
+		result, err = pokeapi.PrefetchPokemon(cfg.requestContext(), cfg.PokeapiClient, from, to, pokeapi.DefaultPrefetchWorkers, printProgress)
+		what = "pokemon"
+
+	default:
+		return fmt.Errorf("error: unknown prefetch subcommand %s (use locations or pokemon <from-to>)", args[0])
+	}
+
+	// prefetch check (cancelled or couldn't list what to fetch)
+	if err != nil {
+		return fmt.Errorf("error prefetching %s: %w", what, err)
+	}
+
+	// summary
+	fmt.Printf("Prefetched %d %s.\n", result.Fetched, what)
+	if len(result.Failed) > 0 {
+		fmt.Printf("Failed to fetch %d: %s\n", len(result.Failed), strings.Join(result.Failed, ", "))
+	}
+
+	// return success
+	return nil
+}
+
+// parseIDRange parses "1-151" (or a single id like "25") into from, to
+func parseIDRange(arg string) (int, int, error) {
+	// split on the dash, a single id is a range of one
+	fromStr, toStr, found := strings.Cut(arg, "-")
+	if !found {
+		toStr = fromStr
+	}
+
+	// parse both ends
+	from, err := strconv.Atoi(fromStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error: invalid pokemon id range %s (use eg 1-151)", arg)
+	}
+	to, err := strconv.Atoi(toStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error: invalid pokemon id range %s (use eg 1-151)", arg)
+	}
+
+	// order check
+	if from < 1 || to < from {
+		return 0, 0, fmt.Errorf("error: invalid pokemon id range %s (use eg 1-151)", arg)
+	}
+	return from, to, nil
+}
+
 // startREPL starts the Read-Eval-Print-Loop for the Pokedex CLI
-func startREPL(pokeClient pokeapi.Client) {
+func startREPL(pokeClient pokeapi.API, pokedex *pokeapi.Pokedex, cache *pokecache.Cache, catchFormula string) {
 	// block until user input
 	scanner := bufio.NewScanner(os.Stdin) // wait for input
 	commands := getCommands()             // get all commands
 	cfg := &config{
-		PokeapiClient: pokeClient,           // store client in config
-		Pokedex:       pokeapi.NewPokedex(), // store pokedex in config,
+		PokeapiClient: pokeClient, // store client in config
+		Pokedex:       pokedex,    // store pokedex in config (loaded from disk in main)
+		Cache:         cache,      // store cache in config for the cache command
+		CatchFormula:  catchFormula,
+		Inventory:     pokedex.Inventory(), // bag is saved with the pokedex
 	} // init config ptr for NEXT & PREVIOUS pagination
 
 	// infinite loop
@@ -424,7 +1209,10 @@ func startREPL(pokeClient pokeapi.Client) {
 		fmt.Print("Pokedex > ") // no newline
 
 		// get user input and clean
-		scanner.Scan()                        // read the user input
+		if !scanner.Scan() { // read the user input, false on EOF (Ctrl+D)
+			fmt.Println() // finish the prompt line
+			return
+		}
 		userInput := scanner.Text()           // get the user input
 		cleanedInput := cleanInput(userInput) // clean the input (lowercase,no WS, slice)
 
@@ -434,18 +1222,30 @@ func startREPL(pokeClient pokeapi.Client) {
 		}
 
 		commandInput := cleanedInput[0] // get command (first word) from input
-		args := cleanedInput[1:]        // get args (rest of words) from input
 
 		// loop through command list to check if input exists (registry lookup)
-		command, ok := commands[commandInput] // see if input exists here
+		command, ok := commands[commandInput]   // see if input exists here
+		args := commandArgs(command, userInput) // get args (rest of words) from input
 
 		// if it exists callback it
 		if ok {
+			// Ctrl+C cancels this command only, outside commands it still quits as usual
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			cfg.Ctx = ctx // commands pass this to the api client
+
 			err := command.callback(cfg, args) // return callback err value to var
 			// CORE: need to pass config file here to call funcs to allow pagination
 
+			// restore default Ctrl+C handling
+			stop()
+			cfg.Ctx = nil
+
 			// callback check
-			if err != nil {
+			if errors.Is(err, errExit) {
+				return // exit command, main cleans up
+			} else if errors.Is(err, context.Canceled) {
+				fmt.Println("\ncommand cancelled") // Ctrl+C mid request
+			} else if err != nil {
 				fmt.Println(err) // Errorf doesn't work here, we don't have error output
 			}
 			continue // Skip the "Invalid command" message when command is valid
@@ -456,6 +1256,16 @@ func startREPL(pokeClient pokeapi.Client) {
 	}
 }
 
+// commandArgs gets command's args (every word after the command) from the raw input line
+// lowercased like the command word, unless the command keeps them as typed
+func commandArgs(command cliCommand, text string) []string {
+	// free text check
+	if command.keepCase {
+		return strings.Fields(text)[1:] // caller made sure there's a command word
+	}
+	return cleanInput(text)[1:]
+}
+
 // make the words lower cases + plit words by whitespace (removes WS)
 func cleanInput(text string) []string {
 	// empty str edge case
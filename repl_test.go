@@ -1,7 +1,19 @@
 // repl_test.go
 package main
 
-import "testing" // importing testing package for unit tests
+import (
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing" // importing testing package for unit tests
+	"time"
+
+	"github.com/PietPadda/pokedexcli/internal/pokeapi"
+	"github.com/PietPadda/pokedexcli/internal/pokeapi/pokeapitest"
+	"github.com/PietPadda/pokedexcli/internal/pokecache"
+)
 
 func TestCleanInput(t *testing.T) {
 	// our unit tests
@@ -53,3 +65,487 @@ func TestCleanInput(t *testing.T) {
 		}
 	}
 }
+
+// captureOutput runs fn and returns everything it printed to stdout
+func captureOutput(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// read in the background so big outputs can't fill the pipe and block fn
+	out := make(chan string)
+	go func() {
+		raw, _ := io.ReadAll(r)
+		out <- string(raw)
+	}()
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+	return <-out
+}
+
+// newTestConfig builds a config backed by the offline fake client and a seeded random source
+func newTestConfig(t *testing.T) *config {
+	cache := pokecache.NewCache(time.Minute)
+	t.Cleanup(func() { cache.Close() })
+
+	pokedex := pokeapi.NewPokedex()
+	return &config{
+		PokeapiClient: pokeapitest.New(),
+		Pokedex:       pokedex,
+		Cache:         cache,
+		Rand:          rand.New(rand.NewSource(1)),
+		Inventory:     pokedex.Inventory(),
+	}
+}
+
+// standingIn returns a setup that puts the player in a made up area where the named pokemon always turn up
+func standingIn(names ...string) func(t *testing.T, cfg *config) {
+	return func(t *testing.T, cfg *config) {
+		area := pokeapi.LocationAreaDetails{Name: "test-area"}
+		for _, name := range names {
+			area.PokemonEncounters = append(area.PokemonEncounters, pokeapi.PokemonEncounter{Pokemon: pokeapi.Pokemon{Name: name}})
+		}
+		cfg.Location = &area
+	}
+}
+
+// emptyBag is a setup that uses up every item in the bag
+func emptyBag(t *testing.T, cfg *config) {
+	for name, count := range cfg.Inventory.Items() {
+		for i := 0; i < count; i++ {
+			if err := cfg.Inventory.Use(name); err != nil {
+				t.Fatalf("Use(%s) unsuccesful: %v", name, err)
+			}
+		}
+	}
+}
+
+func TestCommands(t *testing.T) {
+	cases := []struct {
+		name        string
+		setup       func(t *testing.T, cfg *config) // optional, runs before the inputs
+		inputs      []string                        // REPL lines, run in order
+		expected    []string                        // substrings the output must contain
+		notExpected []string                        // substrings the output must NOT contain
+		expectErr   string                          // substring of the last command's error (empty = no error)
+	}{
+		{
+			name:     "help",
+			inputs:   []string{"help"},
+			expected: []string{"Usage:", "map: ", "catch: ", "pokedex: "},
+		},
+		{
+			name:        "map first page",
+			inputs:      []string{"map"},
+			expected:    []string{"Location Areas:", "canalave-city-area", "sinnoh-pokemon-league-area"},
+			notExpected: []string{"mt-coronet-b1f"},
+		},
+		{
+			name:     "map second page",
+			inputs:   []string{"map", "map"},
+			expected: []string{"mt-coronet-b1f"},
+		},
+		{
+			name:     "mapb back to first page",
+			inputs:   []string{"map", "map", "mapb"},
+			expected: []string{"canalave-city-area"},
+		},
+		{
+			name:     "explore",
+			inputs:   []string{"explore canalave-city-area"},
+			expected: []string{"Exploring canalave-city-area...", "Found Pokemon:", "- tentacool", "- gyarados"},
+		},
+		{
+			name:     "explore empty area",
+			inputs:   []string{"explore sinnoh-pokemon-league-area"},
+			expected: []string{"No Pokemon were found at this location."},
+		},
+		{
+			name:      "explore missing arg",
+			inputs:    []string{"explore"},
+			expectErr: "explore must take location area name",
+		},
+		{
+			name:      "explore unknown area",
+			inputs:    []string{"explore atlantis"},
+			expectErr: "no location area named atlantis",
+		},
+		{
+			name:     "explore shows encounter chances",
+			inputs:   []string{"explore canalave-city-area"},
+			expected: []string{"- tentacool (60%)", "- magikarp (100%)", "- gyarados (40%)"},
+		},
+		{
+			name:     "catch",
+			inputs:   []string{"explore canalave-city-area", "catch magikarp", "catch magikarp", "catch magikarp"},
+			expected: []string{"Throwing Pokeball at magikarp...", "magikarp was caught!"},
+		},
+		{
+			name:      "catch before exploring",
+			inputs:    []string{"catch magikarp"},
+			expectErr: "you haven't explored anywhere yet",
+		},
+		{
+			name:      "catch not in area",
+			inputs:    []string{"explore canalave-city-area", "catch pikachu"},
+			expectErr: "no pikachu at canalave-city-area",
+		},
+		{
+			name:      "catch typo suggests area names",
+			inputs:    []string{"explore canalave-city-area", "catch magikrp"},
+			expectErr: "no magikrp at canalave-city-area \u2014 did you mean magikarp?",
+		},
+		{
+			name:      "catch typo suggests names",
+			inputs:    []string{"explore canalave-city-area", "catch pikachoo"},
+			expectErr: "no Pokemon named pikachoo \u2014 did you mean pikachu?",
+		},
+		{
+			name:      "catch area pokemon the api doesn't know",
+			setup:     standingIn("pikachuu"),
+			inputs:    []string{"catch pikachuu"},
+			expectErr: "no Pokemon named pikachuu \u2014 did you mean pikachu?",
+		},
+		{
+			name:      "catch typo without suggestions",
+			inputs:    []string{"explore canalave-city-area", "catch zzzzzz"},
+			expectErr: "no Pokemon named zzzzzz",
+		},
+		{
+			name:        "catch rare encounter",
+			inputs:      []string{"explore canalave-city-area", "catch gyarados", "inventory"},
+			expected:    []string{"You searched canalave-city-area but couldn't find a gyarados.", "Poké Ball x20"}, // no ball thrown
+			notExpected: []string{"Throwing"},
+		},
+		{
+			name:     "surf",
+			inputs:   []string{"explore canalave-city-area", "surf"},
+			expected: []string{"A wild tentacool (Lv. ", "Use catch to throw a ball at it."},
+		},
+		{
+			name:     "fish with a rod",
+			inputs:   []string{"explore canalave-city-area", "fish super"},
+			expected: []string{"A wild gyarados (Lv. "},
+		},
+		{
+			name:     "walk with nothing in the grass",
+			inputs:   []string{"explore canalave-city-area", "walk"},
+			expected: []string{"No Pokemon at canalave-city-area can be met by walk."},
+		},
+		{
+			name:      "walk before exploring",
+			inputs:    []string{"walk"},
+			expectErr: "you haven't explored anywhere yet",
+		},
+		{
+			name:      "fish unknown rod",
+			inputs:    []string{"explore canalave-city-area", "fish net"},
+			expectErr: "unknown rod net",
+		},
+		{
+			name:        "catch wild encounter",
+			inputs:      []string{"explore canalave-city-area", "fish super", "catch --ball master", "catch"},
+			expected:    []string{"Throwing Master Ball at gyarados...", "gyarados was caught!", "Sent to your box as #1 (Lv. "},
+			notExpected: []string{"couldn't find"},      // it was already in front of us
+			expectErr:   "catch must take pokemon name", // and gone after the throw
+		},
+		{
+			name:     "catch weakened",
+			inputs:   []string{"explore canalave-city-area", "catch magikarp --hp 1 --status sleep"},
+			expected: []string{"magikarp was caught!"},
+		},
+		{
+			name:     "catch legendary",
+			setup:    standingIn("mewtwo"),
+			inputs:   []string{"catch mewtwo"},
+			expected: []string{"mewtwo is a legendary Pokemon, good luck!", "mewtwo escaped!"},
+		},
+		{
+			name: "catch classic formula",
+			setup: func(t *testing.T, cfg *config) {
+				standingIn("mewtwo")(t, cfg)
+				cfg.CatchFormula = catchFormulaClassic
+			},
+			inputs:      []string{"catch mewtwo"},
+			expected:    []string{"Throwing Pokeball at mewtwo..."},
+			notExpected: []string{"legendary"},
+		},
+		{
+			name:      "catch bad option",
+			inputs:    []string{"catch magikarp --net big"},
+			expectErr: "unknown catch option --net",
+		},
+		{
+			name:        "catch master ball",
+			setup:       standingIn("mewtwo"),
+			inputs:      []string{"catch mewtwo --ball master", "inventory"},
+			expected:    []string{"Throwing Master Ball at mewtwo...", "mewtwo was caught!", "Your bag:", " - Poké Ball x20: Tries to catch a wild Pokémon."},
+			notExpected: []string{"Master Ball x"},
+		},
+		{
+			name:        "catch ultra ball",
+			setup:       standingIn("mewtwo"),
+			inputs:      []string{"catch mewtwo --ball ultra"},
+			expected:    []string{"Throwing Ultra Ball at mewtwo..."},
+			notExpected: []string{"a Ultra Ball"},
+		},
+		{
+			name:      "catch out of balls",
+			setup:     standingIn("mewtwo"),
+			inputs:    []string{"catch mewtwo --ball master", "catch mewtwo --ball master"},
+			expectErr: "you have no Master Balls left",
+		},
+		{
+			name:     "inventory empty",
+			setup:    emptyBag,
+			inputs:   []string{"inventory"},
+			expected: []string{"Your bag is empty, thrown balls don't come back."},
+		},
+		{
+			name:      "catch with an empty bag",
+			setup:     func(t *testing.T, cfg *config) { emptyBag(t, cfg); standingIn("mewtwo")(t, cfg) },
+			inputs:    []string{"catch mewtwo"},
+			expectErr: "you have no Pokeballs left, thrown balls don't come back (see inventory)",
+		},
+		{
+			name:     "inventory item details",
+			inputs:   []string{"inventory great"},
+			expected: []string{"Great Ball x10", "success rate ×1.5", "Cost: 600", "Sprite: https://"},
+		},
+		{
+			name:      "inventory unknown item",
+			inputs:    []string{"inventory potion"},
+			expectErr: "no item named potion",
+		},
+		{
+			name:      "catch missing arg",
+			inputs:    []string{"catch"},
+			expectErr: "catch must take pokemon name",
+		},
+		{
+			name:     "inspect not caught",
+			inputs:   []string{"inspect pikachu"},
+			expected: []string{"you have not caught that pokemon"},
+		},
+		{
+			name: "inspect caught",
+			setup: func(t *testing.T, cfg *config) {
+				stats, _ := cfg.PokeapiClient.GetPokemonStatsContext(cfg.requestContext(), "pikachu")
+				cfg.Pokedex.PokemonAdd("pikachu", stats)
+			},
+			inputs:   []string{"inspect pikachu"},
+			expected: []string{"Name: pikachu", "Height: 4", "Weight: 60", "-speed: 90", "- electric"},
+		},
+		{
+			name:     "pokedex empty",
+			inputs:   []string{"pokedex"},
+			expected: []string{"You have not caught any pokemon yet!"},
+		},
+		{
+			name: "pokedex",
+			setup: func(t *testing.T, cfg *config) {
+				cfg.Pokedex.PokemonAdd("mewtwo", pokeapi.PokemonStats{Name: "mewtwo"})
+			},
+			inputs:   []string{"pokedex"},
+			expected: []string{"Your Pokedex:", " - mewtwo"},
+		},
+		{
+			name:     "pokedex seen",
+			inputs:   []string{"explore canalave-city-area", "surf", "pokedex"},
+			expected: []string{"Seen: 1, Caught: 0", " - tentacool (seen)"},
+		},
+		{
+			name:     "box",
+			setup:    standingIn("mewtwo"),
+			inputs:   []string{"catch mewtwo --ball master", "box"},
+			expected: []string{"Sent to your box as #1 (Lv. 5, ", "Your box:", " - #1 mewtwo Lv. 5"},
+		},
+		{
+			name:     "box empty",
+			inputs:   []string{"box"},
+			expected: []string{"Your box is empty."},
+		},
+		{
+			name:     "box details",
+			setup:    standingIn("mewtwo"),
+			inputs:   []string{"catch mewtwo --ball master", "box #1"},
+			expected: []string{"#1 mewtwo Lv. 5", "Nature: ", "IVs: HP ", "Caught at test-area on "},
+		},
+		{
+			name:     "nickname and release",
+			setup:    standingIn("mewtwo"),
+			inputs:   []string{"catch mewtwo --ball master", "nickname 1 mew", "box", "release 1", "box", "pokedex"},
+			expected: []string{"#1 mewtwo is now called mew.", " - #1 mew (mewtwo) Lv. 5", "Bye bye, mew!", "Your box is empty.", " - mewtwo"},
+		},
+		{
+			name:     "nickname keeps case",
+			setup:    standingIn("mewtwo"),
+			inputs:   []string{"CATCH Mewtwo --ball master", "Nickname #1 Sparky the Great", "box 1"},
+			expected: []string{"#1 mewtwo is now called Sparky the Great.", "#1 Sparky the Great (mewtwo) Lv. 5"},
+		},
+		{
+			name:      "nickname bad id",
+			inputs:    []string{"nickname pikachu sparky"},
+			expectErr: "nickname takes a box id",
+		},
+		{
+			name:      "release not in box",
+			inputs:    []string{"release 7"},
+			expectErr: "no #7 in your box",
+		},
+		{
+			name: "save and load",
+			setup: func(t *testing.T, cfg *config) {
+				cfg.Pokedex.SetSavePath(filepath.Join(t.TempDir(), "pokedex.json"))
+				cfg.Pokedex.PokemonAdd("mewtwo", pokeapi.PokemonStats{Name: "mewtwo"})
+			},
+			inputs:   []string{"save", "load", "pokedex"},
+			expected: []string{"Pokedex saved to", "Pokedex loaded from", " - mewtwo"},
+		},
+		{
+			name: "cache stats",
+			setup: func(t *testing.T, cfg *config) {
+				cfg.Cache.CacheAdd("https://pokeapi.co/api/v2/pokemon/pikachu", []byte("{}"))
+				cfg.Cache.CacheGet("https://pokeapi.co/api/v2/pokemon/pikachu")
+				cfg.Cache.CacheGet("https://pokeapi.co/api/v2/pokemon/raichu")
+			},
+			inputs:   []string{"cache stats"},
+			expected: []string{"Entries: 1", "Hits: 1", "Misses: 1", "Hit rate: 50.0%"},
+		},
+		{
+			name: "cache list",
+			setup: func(t *testing.T, cfg *config) {
+				cfg.Cache.CacheAdd("https://pokeapi.co/api/v2/pokemon/pikachu", []byte("{}"))
+			},
+			inputs:   []string{"cache list"},
+			expected: []string{"Cached entries:", " - https://pokeapi.co/api/v2/pokemon/pikachu ("},
+		},
+		{
+			name: "cache evict and clear",
+			setup: func(t *testing.T, cfg *config) {
+				cfg.Cache.CacheAdd("https://pokeapi.co/api/v2/pokemon/pikachu", []byte("{}"))
+				cfg.Cache.CacheAdd("https://pokeapi.co/api/v2/pokemon/raichu", []byte("{}"))
+			},
+			inputs:   []string{"cache evict https://pokeapi.co/api/v2/pokemon/pikachu", "cache list", "cache clear", "cache list"},
+			expected: []string{"Evicted https://pokeapi.co/api/v2/pokemon/pikachu", "Cache cleared.", "The cache is empty."},
+		},
+		{
+			name: "cache evict mixed case key",
+			setup: func(t *testing.T, cfg *config) {
+				cfg.Cache.CacheAdd("https://pokeapi.co/api/v2/location-area/?offset=20&Limit=20", []byte("{}"))
+			},
+			inputs:      []string{"CACHE Evict https://pokeapi.co/api/v2/location-area/?offset=20&Limit=20", "cache list"},
+			expected:    []string{"Evicted https://pokeapi.co/api/v2/location-area/?offset=20&Limit=20", "The cache is empty."},
+			notExpected: []string{"was not cached"},
+		},
+		{
+			name:      "cache unknown subcommand",
+			inputs:    []string{"cache flush"},
+			expectErr: "unknown cache subcommand flush",
+		},
+		{
+			name:     "prefetch pokemon",
+			inputs:   []string{"prefetch pokemon 129-130"},
+			expected: []string{"Prefetching pokemon 129-130...", "2/2", "Prefetched 2 pokemon."},
+		},
+		{
+			name:     "prefetch pokemon reports failures",
+			inputs:   []string{"prefetch pokemon 25-26"},
+			expected: []string{"Prefetched 1 pokemon.", "Failed to fetch 1: raichu"},
+		},
+		{
+			name:     "prefetch locations",
+			inputs:   []string{"prefetch locations"},
+			expected: []string{"Prefetching location areas...", "8/8", "Prefetched 2 location areas."},
+		},
+		{
+			name:      "prefetch bad range",
+			inputs:    []string{"prefetch pokemon 151-1"},
+			expectErr: "invalid pokemon id range 151-1",
+		},
+		{
+			name:      "load missing save",
+			inputs:    []string{"load"},
+			expectErr: "no save path configured",
+		},
+	}
+
+	commands := getCommands()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			if c.setup != nil {
+				c.setup(t, cfg)
+			}
+
+			var err error
+			output := captureOutput(t, func() {
+				for _, input := range c.inputs {
+					words := cleanInput(input)
+					command, ok := commands[words[0]]
+					if !ok {
+						t.Fatalf("unknown command %q", words[0])
+					}
+					err = command.callback(cfg, commandArgs(command, input))
+				}
+			})
+
+			// only the last command's error matters, earlier steps are setup
+			if c.expectErr == "" && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if c.expectErr != "" && (err == nil || !strings.Contains(err.Error(), c.expectErr)) {
+				t.Errorf("expected error containing %q, got %v", c.expectErr, err)
+			}
+			for _, expected := range c.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("expected output to contain %q, got:\n%s", expected, output)
+				}
+			}
+			for _, notExpected := range c.notExpected {
+				if strings.Contains(output, notExpected) {
+					t.Errorf("expected output to NOT contain %q, got:\n%s", notExpected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveLoadPathKeepsCase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "MyDex.json")
+	commands := getCommands()
+	run := func(cfg *config, input string) error {
+		command := commands[cleanInput(input)[0]]
+		return command.callback(cfg, commandArgs(command, input))
+	}
+
+	// save to a mixed case path
+	cfg := newTestConfig(t)
+	cfg.Pokedex.PokemonAdd("mewtwo", pokeapi.PokemonStats{Name: "mewtwo"})
+	captureOutput(t, func() {
+		if err := run(cfg, "save "+path); err != nil {
+			t.Fatalf("save unsuccesful: %v", err)
+		}
+	})
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected save at %s: %v", path, err)
+	}
+
+	// load it back into a fresh pokedex
+	loaded := newTestConfig(t)
+	captureOutput(t, func() {
+		if err := run(loaded, "LOAD "+path); err != nil {
+			t.Fatalf("load unsuccesful: %v", err)
+		}
+	})
+	if _, ok, _ := loaded.Pokedex.PokemonGet("mewtwo"); !ok {
+		t.Errorf("expected mewtwo after loading %s", path)
+	}
+}
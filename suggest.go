@@ -0,0 +1,74 @@
+// suggest.go
+package main // all files in same folder form part of package main
+
+import (
+	// import standard libraries
+	"sort" // for ordering suggestions by closeness
+)
+
+// max suggestions shown in "did you mean" messages
+const maxSuggestions = 3
+
+// suggestNames returns up to maxSuggestions candidates closest to name by edit distance
+// only reasonably close names are returned (typos, not random guesses)
+func suggestNames(name string, candidates []string) []string {
+	// allowed typo distance grows with name length
+	maxDistance := len(name) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	// candidate + its distance for sorting
+	type match struct {
+		name     string
+		distance int
+	}
+	var matches []match
+
+	// loop thru candidates and keep close ones
+	for _, candidate := range candidates {
+		distance := editDistance(name, candidate)
+		if distance <= maxDistance {
+			matches = append(matches, match{name: candidate, distance: distance})
+		}
+	}
+
+	// closest first, then alphabetical so output is stable
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	// take the best few
+	names := make([]string, 0, maxSuggestions)
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		names = append(names, matches[i].name)
+	}
+	return names
+}
+
+// editDistance returns the Levenshtein distance between a and b
+// (single character inserts, deletes and swaps needed to turn a into b)
+func editDistance(a, b string) int {
+	// two rows of the dp table are enough
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j // distance from empty a
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i // distance to empty b
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost) // delete, insert, substitute
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
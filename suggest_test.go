@@ -0,0 +1,27 @@
+// suggest_test.go
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSuggestNames(t *testing.T) {
+	candidates := []string{"pikachu", "raichu", "pichu", "bulbasaur", "charmander", "charmeleon"}
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "pikachoo", expected: []string{"pikachu"}},
+		{input: "charmandr", expected: []string{"charmander"}},
+		{input: "pichu", expected: []string{"pichu", "pikachu", "raichu"}},
+		{input: "mewtwo", expected: []string{}},
+	}
+
+	for _, c := range cases {
+		actual := suggestNames(c.input, candidates)
+		if strings.Join(actual, ",") != strings.Join(c.expected, ",") {
+			t.Errorf("suggestNames(%q): expected %v, got %v", c.input, c.expected, actual)
+		}
+	}
+}